@@ -2,21 +2,18 @@ package cli
 
 import (
 	"context"
-	"crypto/tls"
 	"errors"
-	"net"
+	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
-	"time"
+	"text/template"
 
-	"github.com/alitto/pond"
-	"github.com/apex/log"
 	"github.com/dpogorzelski/speedrun/pkg/speedrun/cloud"
+	"github.com/dpogorzelski/speedrun/pkg/speedrun/executor"
 	portalpb "github.com/dpogorzelski/speedrun/proto/portal"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
-	"storj.io/drpc/drpcconn"
 )
 
 var fileCmd = &cobra.Command{
@@ -36,7 +33,7 @@ var readCmd = &cobra.Command{
 var cpCmd = &cobra.Command{
 	Use:     "cp <src> <dst>",
 	Short:   "Copy a file",
-	Example: "  speedrun file cp myfile :/tmp/myfile\n  speedrun file cp :/tmp/myfile myfile\n  speedrun file cp :/tmp/myfile :/tmp/mynewfile",
+	Example: "  speedrun file cp myfile :/tmp/myfile\n  speedrun file cp :/tmp/myfile myfile\n  speedrun file cp :/etc/hosts \"./dump/{{.Name}}/hosts\"\n  speedrun file cp :/tmp/myfile :/tmp/mynewfile",
 	Args:    cobra.MinimumNArgs(2),
 	RunE:    cp,
 }
@@ -51,81 +48,48 @@ var chmodCmd = &cobra.Command{
 
 func init() {
 	fileCmd.SetUsageTemplate(usage)
+	addOutputFlags(fileCmd)
 	fileCmd.AddCommand(readCmd)
 	fileCmd.AddCommand(cpCmd)
 	fileCmd.AddCommand(chmodCmd)
 }
 
 func read(cmd *cobra.Command, args []string) error {
-	usePrivateIP := viper.GetBool("portal.use-private-ip")
-
-	tlsConfig, err := cloud.SetupTLS()
+	out, err := newReporter(cmd)
 	if err != nil {
 		return err
 	}
 
-	target, err := cmd.Flags().GetString("target")
+	portals, err := targetInstances(cmd)
 	if err != nil {
 		return err
 	}
-
-	portals, err := cloud.GetInstances(target)
-	if err != nil {
+	if err := out.checkFiles(portals); err != nil {
 		return err
 	}
 
-	pool := pond.New(1000, 10000)
-	for _, p := range portals {
-		portal := p
-		pool.Submit(func() {
-			fields := log.Fields{
-				"host":    portal.Name,
-				"address": portal.GetAddress(usePrivateIP),
-			}
-			log := log.WithFields(fields)
-
-			addr := net.JoinHostPort(portal.GetAddress(usePrivateIP), "1337")
-			rawconn, err := tls.Dial("tcp", addr, tlsConfig)
-			if err != nil {
-				log.Error(err.Error())
-				return
-			}
-
-			conn := drpcconn.New(rawconn)
-			defer conn.Close()
-
-			c := portalpb.NewDRPCPortalClient(conn)
-			ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
-			defer cancel()
-
-			path := strings.Join(args, " ")
-			r, err := c.FileRead(ctx, &portalpb.FileReadRequest{Path: path})
-			if err != nil {
-				log.Error(err.Error())
-				return
-			}
-			log.WithField("state", r.GetState()).Infof("Contents of %s:", path)
-			println(r.GetContent())
-
-		})
-	}
-	pool.StopAndWait()
-	return nil
-}
-
-func cp(cmd *cobra.Command, args []string) error {
-	usePrivateIP := viper.GetBool("portal.use-private-ip")
-
-	tlsConfig, err := cloud.SetupTLS()
+	fleet, err := newExecutor(len(portals))
 	if err != nil {
 		return err
 	}
 
-	target, err := cmd.Flags().GetString("target")
-	if err != nil {
+	path := strings.Join(args, " ")
+	op := func(ctx context.Context, c portalpb.DRPCPortalClient, _ cloud.Instance) (*executor.Response, error) {
+		r, err := c.FileRead(ctx, &portalpb.FileReadRequest{Path: path})
+		if err != nil {
+			return nil, err
+		}
+		return &executor.Response{State: r.GetState(), Stdout: r.GetContent()}, nil
+	}
+
+	results := fleet.Run(context.Background(), portals, op, out.result)
+	if err := out.flush(results); err != nil {
 		return err
 	}
+	return checkResults(results)
+}
 
+func cp(cmd *cobra.Command, args []string) error {
 	remoteSrc := strings.HasPrefix(args[0], ":")
 	remoteDst := strings.HasPrefix(args[1], ":")
 
@@ -134,6 +98,7 @@ func cp(cmd *cobra.Command, args []string) error {
 	}
 
 	var content []byte
+	var err error
 	if !remoteSrc {
 		content, err = os.ReadFile(args[0])
 		if err != nil {
@@ -141,111 +106,143 @@ func cp(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	portals, err := cloud.GetInstances(target)
+	out, err := newReporter(cmd)
 	if err != nil {
 		return err
 	}
 
-	pool := pond.New(1000, 10000)
-	for _, p := range portals {
-		portal := p
-		pool.Submit(func() {
-			fields := log.Fields{
-				"host":    portal.Name,
-				"address": portal.GetAddress(usePrivateIP),
-			}
-			log := log.WithFields(fields)
-
-			addr := net.JoinHostPort(portal.GetAddress(usePrivateIP), "1337")
-			rawconn, err := tls.Dial("tcp", addr, tlsConfig)
-			if err != nil {
-				log.Error(err.Error())
-				return
-			}
+	portals, err := targetInstances(cmd)
+	if err != nil {
+		return err
+	}
+	if err := out.checkFiles(portals); err != nil {
+		return err
+	}
 
-			conn := drpcconn.New(rawconn)
-			defer conn.Close()
+	fleet, err := newExecutor(len(portals))
+	if err != nil {
+		return err
+	}
 
-			c := portalpb.NewDRPCPortalClient(conn)
-			ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
-			defer cancel()
+	var localPath func(cloud.Instance) (string, error)
+	if !remoteDst {
+		localPath, err = downloadPath(strings.TrimPrefix(args[0], ":"), args[1], out.dir != "", portals)
+		if err != nil {
+			return err
+		}
+	}
 
-			r, err := c.FileCp(ctx, &portalpb.FileCpRequest{Src: strings.TrimPrefix(args[0], ":"), Dst: strings.TrimPrefix(args[1], ":"), Content: content, RemoteSrc: remoteSrc, RemoteDst: remoteDst})
+	op := func(ctx context.Context, c portalpb.DRPCPortalClient, instance cloud.Instance) (*executor.Response, error) {
+		r, err := c.FileCp(ctx, &portalpb.FileCpRequest{Src: strings.TrimPrefix(args[0], ":"), Dst: strings.TrimPrefix(args[1], ":"), Content: content, RemoteSrc: remoteSrc, RemoteDst: remoteDst})
+		if err != nil {
+			return nil, err
+		}
+		if !remoteDst {
+			dst, err := localPath(instance)
 			if err != nil {
-				log.Error(err.Error())
-				return
+				return nil, err
 			}
-			if !remoteDst {
-				err := os.WriteFile(args[1], r.GetContent(), 0644)
-				if err != nil {
-					log.Error(err.Error())
-					return
-				}
+			if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+				return nil, err
 			}
+			if err := os.WriteFile(dst, r.GetContent(), 0644); err != nil {
+				return nil, err
+			}
+			return &executor.Response{State: r.GetState(), Message: fmt.Sprintf("Saved to %s", dst)}, nil
+		}
+		return &executor.Response{State: r.GetState()}, nil
+	}
 
-			log.WithField("state", r.GetState()).Infof("Done")
-		})
+	results := fleet.Run(context.Background(), portals, op, out.result)
+	if err := out.flush(results); err != nil {
+		return err
 	}
-	pool.StopAndWait()
-	return nil
+	return checkResults(results)
 }
 
-func chmod(cmd *cobra.Command, args []string) error {
-	usePrivateIP := viper.GetBool("portal.use-private-ip")
+// downloadPath returns the function giving the local path the file
+// downloaded from an instance is saved to. dst is either a plain path or a
+// template rendered with the instance, e.g. ./dump/{{.Name}}/hosts. With
+// perHost set, a plain dst is treated as a directory holding a subdirectory
+// per host. Paths that would be shared by several instances are refused.
+func downloadPath(src, dst string, perHost bool, instances []cloud.Instance) (func(cloud.Instance) (string, error), error) {
+	var path func(cloud.Instance) (string, error)
+	switch {
+	case strings.Contains(dst, "{{"):
+		tmpl, err := template.New("dst").Option("missingkey=error").Parse(dst)
+		if err != nil {
+			return nil, fmt.Errorf("invalid destination template: %v", err)
+		}
+		path = func(instance cloud.Instance) (string, error) {
+			var b strings.Builder
+			if err := tmpl.Execute(&b, instance); err != nil {
+				return "", fmt.Errorf("invalid destination template: %v", err)
+			}
+			return b.String(), nil
+		}
+	case perHost:
+		path = func(instance cloud.Instance) (string, error) {
+			return filepath.Join(dst, hostFile(instance.Name), filepath.Base(src)), nil
+		}
+	default:
+		if len(instances) > 1 {
+			return nil, fmt.Errorf("downloading from %d instances to %s would overwrite it, use a per host destination such as \"%s\" or --output-dir", len(instances), dst, filepath.Join(dst, "{{.Name}}", filepath.Base(src)))
+		}
+		path = func(cloud.Instance) (string, error) {
+			return dst, nil
+		}
+	}
+
+	seen := make(map[string]string, len(instances))
+	for _, i := range instances {
+		p, err := path(i)
+		if err != nil {
+			return nil, err
+		}
+		p = filepath.Clean(p)
+		if other, ok := seen[p]; ok {
+			return nil, fmt.Errorf("instances %s and %s would both be downloaded to %s", other, i.Name, p)
+		}
+		seen[p] = i.Name
+	}
+	return path, nil
+}
 
-	tlsConfig, err := cloud.SetupTLS()
+func chmod(cmd *cobra.Command, args []string) error {
+	filemode, err := strconv.Atoi(args[1])
 	if err != nil {
 		return err
 	}
 
-	target, err := cmd.Flags().GetString("target")
+	out, err := newReporter(cmd)
 	if err != nil {
 		return err
 	}
 
-	filemode, err := strconv.Atoi(args[1])
+	portals, err := targetInstances(cmd)
 	if err != nil {
 		return err
 	}
+	if err := out.checkFiles(portals); err != nil {
+		return err
+	}
 
-	portals, err := cloud.GetInstances(target)
+	fleet, err := newExecutor(len(portals))
 	if err != nil {
 		return err
 	}
 
-	pool := pond.New(1000, 10000)
-	for _, p := range portals {
-		portal := p
-		pool.Submit(func() {
-			fields := log.Fields{
-				"host":    portal.Name,
-				"address": portal.GetAddress(usePrivateIP),
-			}
-			log := log.WithFields(fields)
-
-			addr := net.JoinHostPort(portal.GetAddress(usePrivateIP), "1337")
-			rawconn, err := tls.Dial("tcp", addr, tlsConfig)
-			if err != nil {
-				log.Error(err.Error())
-				return
-			}
-
-			conn := drpcconn.New(rawconn)
-			defer conn.Close()
-
-			c := portalpb.NewDRPCPortalClient(conn)
-			ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
-			defer cancel()
-
-			r, err := c.FileChmod(ctx, &portalpb.FileChmodRequest{Path: args[0], Filemode: uint32(filemode)})
-			if err != nil {
-				log.Error(err.Error())
-				return
-			}
+	op := func(ctx context.Context, c portalpb.DRPCPortalClient, _ cloud.Instance) (*executor.Response, error) {
+		r, err := c.FileChmod(ctx, &portalpb.FileChmodRequest{Path: args[0], Filemode: uint32(filemode)})
+		if err != nil {
+			return nil, err
+		}
+		return &executor.Response{State: r.GetState()}, nil
+	}
 
-			log.WithField("state", r.GetState()).Infof("Done")
-		})
+	results := fleet.Run(context.Background(), portals, op, out.result)
+	if err := out.flush(results); err != nil {
+		return err
 	}
-	pool.StopAndWait()
-	return nil
+	return checkResults(results)
 }
@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/dpogorzelski/speedrun/pkg/speedrun/cloud"
+	"github.com/dpogorzelski/speedrun/pkg/speedrun/executor"
+	portalpb "github.com/dpogorzelski/speedrun/proto/portal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// targetInstances returns the instances matching the --target flag
+func targetInstances(cmd *cobra.Command) ([]cloud.Instance, error) {
+	target, err := cmd.Flags().GetString("target")
+	if err != nil {
+		return nil, err
+	}
+
+	return cloud.GetInstances(target)
+}
+
+// newExecutor returns an executor set up with the TLS, portal and rollout
+// settings for the given number of instances
+func newExecutor(total int) (*executor.Executor, error) {
+	tlsConfig, err := cloud.SetupTLS()
+	if err != nil {
+		return nil, err
+	}
+
+	batchSize, err := executor.ParseSize(viper.GetString("rollout.batch-size"), total)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse batch size: %v", err)
+	}
+
+	canary, err := executor.ParseSize(viper.GetString("rollout.canary"), total)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse canary size: %v", err)
+	}
+
+	return executor.New(executor.Config{
+		TLS:              tlsConfig,
+		Concurrency:      viper.GetInt("portal.concurrency"),
+		DialTimeout:      viper.GetDuration("portal.dial-timeout"),
+		Timeout:          viper.GetDuration("portal.timeout"),
+		Port:             viper.GetInt("portal.port"),
+		PortLabel:        viper.GetString("portal.port-label"),
+		UsePrivateIP:     viper.GetBool("portal.use-private-ip"),
+		BatchSize:        batchSize,
+		BatchPause:       viper.GetDuration("rollout.batch-pause"),
+		HealthCheck:      healthCheck(),
+		Canary:           canary,
+		MaxFailures:      viper.GetInt("rollout.max-failures"),
+		MaxFailureRate:   viper.GetFloat64("rollout.max-failure-rate"),
+		StrictThresholds: viper.GetBool("rollout.strict-thresholds"),
+		OnBatch: func(batch, batches int, instances []cloud.Instance) {
+			if batch == 1 && canary > 0 && canary < total {
+				log.Infof("Starting canary batch with %d instances", len(instances))
+			} else if batches > 1 {
+				log.Infof("Starting batch %d/%d with %d instances", batch, batches, len(instances))
+			}
+		},
+	}), nil
+}
+
+// healthCheck returns the operation that has to succeed on each instance for
+// the rollout to go on, if any
+func healthCheck() executor.Operation {
+	if command := viper.GetString("rollout.health-cmd"); command != "" {
+		return func(ctx context.Context, c portalpb.DRPCPortalClient, _ cloud.Instance) (*executor.Response, error) {
+			r, err := c.RunCommand(ctx, &portalpb.CommandRequest{Name: command, Shell: defaultShell})
+			if err != nil {
+				return nil, err
+			}
+			return commandResponse(r)
+		}
+	}
+
+	if service := viper.GetString("rollout.health-service"); service != "" {
+		return func(ctx context.Context, c portalpb.DRPCPortalClient, _ cloud.Instance) (*executor.Response, error) {
+			r, err := c.ServiceStatus(ctx, &portalpb.ServiceRequest{Name: service})
+			if err != nil {
+				return nil, err
+			}
+			if r.GetActivestate() != "active" {
+				return nil, fmt.Errorf("service %s is %s (%s)", service, r.GetActivestate(), r.GetSubstate())
+			}
+			return &executor.Response{State: r.GetState()}, nil
+		}
+	}
+
+	return nil
+}
+
+// checkResults logs the summary of the run and returns an error carrying the
+// exit code if the operation didn't succeed on every instance
+func checkResults(results []executor.Result) error {
+	s := executor.Summarize(results)
+
+	log.Infof("Summary: %d succeeded (%d changed, %d unchanged), %d failed, %d unreachable, %d skipped",
+		len(s.Succeeded), len(s.Changed), len(s.Unchanged), len(s.Failed), len(s.Unreachable), len(s.Skipped))
+	logHosts(log.Info, "Changed", s.Changed)
+	logHosts(log.Info, "Unchanged", s.Unchanged)
+	logHosts(log.Error, "Failed", s.Failed)
+	logHosts(log.Error, "Unreachable", s.Unreachable)
+	logHosts(log.Warn, "Skipped", s.Skipped)
+
+	// skipped instances were never contacted, an aborted rollout left them
+	// untouched rather than failed
+	contacted := s.Total - len(s.Skipped)
+	failed := contacted - len(s.Succeeded)
+	switch {
+	case s.Failures() == 0:
+		return nil
+	case len(s.Skipped) > 0:
+		return &exitError{code: exitPartialFailure, err: fmt.Errorf("rollout aborted after the operation failed on %d out of %d contacted instances, %d instances skipped", failed, contacted, len(s.Skipped))}
+	case len(s.Succeeded) == 0:
+		return &exitError{code: exitTotalFailure, err: fmt.Errorf("operation failed on all %d instances", s.Total)}
+	default:
+		return &exitError{code: exitPartialFailure, err: fmt.Errorf("operation failed on %d out of %d instances", s.Failures(), s.Total)}
+	}
+}
+
+// logHosts logs the given hosts, if any, under the given heading
+func logHosts(logf func(string), heading string, hosts []string) {
+	if len(hosts) > 0 {
+		logf(fmt.Sprintf("%s: %s", heading, strings.Join(hosts, ", ")))
+	}
+}
+
+// resultLogger returns a logger carrying the fields that identify the host of r
+func resultLogger(r executor.Result) *log.Entry {
+	fields := log.Fields{
+		"host":     r.Instance.Name,
+		"address":  r.Address,
+		"provider": r.Instance.Provider,
+	}
+	if r.Instance.Project != "" {
+		fields["project"] = r.Instance.Project
+	}
+	return log.WithFields(fields)
+}
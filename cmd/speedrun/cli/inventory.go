@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"github.com/apex/log"
+	"github.com/dpogorzelski/speedrun/pkg/speedrun/cloud"
+	"github.com/spf13/cobra"
+)
+
+var inventoryCmd = &cobra.Command{
+	Use:              "inventory",
+	Short:            "Manage the cached instance list",
+	TraverseChildren: true,
+}
+
+var inventoryRefreshCmd = &cobra.Command{
+	Use:     "refresh",
+	Short:   "Fetch the instance list from the providers and update the cache",
+	Example: "  speedrun inventory refresh",
+	Args:    cobra.NoArgs,
+	RunE:    inventoryRefresh,
+}
+
+func init() {
+	inventoryCmd.SetUsageTemplate(usage)
+	inventoryCmd.AddCommand(inventoryRefreshCmd)
+}
+
+func inventoryRefresh(cmd *cobra.Command, _ []string) error {
+	instances, err := cloud.Refresh()
+	if err != nil {
+		return err
+	}
+
+	counts := map[string]int{}
+	for _, i := range instances {
+		counts[i.Provider]++
+	}
+
+	for provider, count := range counts {
+		log.WithField("provider", provider).Infof("Cached %d instances", count)
+	}
+	return nil
+}
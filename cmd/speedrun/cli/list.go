@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/apex/log"
+	"github.com/dpogorzelski/speedrun/pkg/speedrun/cloud"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var listCmd = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "List the instances matching the target",
+	Example: "  speedrun ls --target \"labels.role == 'nginx'\"\n  speedrun ls --output names",
+	Args:    cobra.NoArgs,
+	RunE:    list,
+}
+
+func init() {
+	listCmd.SetUsageTemplate(usage)
+	listCmd.Flags().StringP("output", "o", "table", "Output format: table, json or names")
+}
+
+func list(cmd *cobra.Command, _ []string) error {
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+
+	if output != "table" && output != "json" && output != "names" {
+		return fmt.Errorf("unknown output format \"%s\", use one of: table, json, names", output)
+	}
+
+	target, err := cmd.Flags().GetString("target")
+	if err != nil {
+		return err
+	}
+
+	instances, err := cloud.GetInstances(target)
+	if err != nil {
+		return err
+	}
+
+	switch output {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(instances); err != nil {
+			return err
+		}
+	case "names":
+		for _, i := range instances {
+			fmt.Println(i.Name)
+		}
+	default:
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tPROVIDER\tPUBLIC ADDRESS\tPRIVATE ADDRESS\tZONE\tSTATUS\tLABELS")
+		for _, i := range instances {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", i.Name, i.Provider, orDash(i.PublicAddress), orDash(i.PrivateAddress), i.Zone, i.Status, formatLabels(i.Labels))
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	private := viper.GetBool("portal.use-private-ip")
+	for _, i := range instances {
+		switch {
+		case i.GetAddress(private) == "":
+			log.Warnf("Instance %s has no address, it can't be reached", i.Name)
+		case private && i.PrivateAddress == "":
+			log.Warnf("Instance %s has no private address, its public one is used", i.Name)
+		case !private && i.PublicAddress == "":
+			log.Debugf("Instance %s has no public address, its private one is used", i.Name)
+		}
+	}
+
+	log.Infof("%d instances matched the target", len(instances))
+	return nil
+}
+
+// orDash makes empty values visible in tables
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// formatLabels renders labels as a sorted, comma separated list of key=value pairs
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
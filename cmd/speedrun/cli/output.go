@@ -0,0 +1,347 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dpogorzelski/speedrun/pkg/speedrun/cloud"
+	"github.com/dpogorzelski/speedrun/pkg/speedrun/executor"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var outputFormats = []string{"text", "json", "ndjson", "yaml", "table"}
+
+// record is the structured outcome of an operation on a single host
+type record struct {
+	Host     string `json:"host" yaml:"host"`
+	Address  string `json:"address" yaml:"address"`
+	State    string `json:"state" yaml:"state"`
+	Message  string `json:"message,omitempty" yaml:"message,omitempty"`
+	Stdout   string `json:"stdout" yaml:"stdout"`
+	Stderr   string `json:"stderr" yaml:"stderr"`
+	ExitCode *int   `json:"exit_code" yaml:"exit_code"`
+	Duration string `json:"duration" yaml:"duration"`
+	Error    string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// groupRecord is the structured form of a group of hosts with identical results
+type groupRecord struct {
+	Hosts    []string `json:"hosts" yaml:"hosts"`
+	Count    int      `json:"count" yaml:"count"`
+	Stdout   string   `json:"stdout" yaml:"stdout"`
+	Stderr   string   `json:"stderr" yaml:"stderr"`
+	ExitCode *int     `json:"exit_code" yaml:"exit_code"`
+	Error    string   `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+func newGroupRecord(g executor.Group) groupRecord {
+	rec := groupRecord{
+		Hosts:    g.Hosts,
+		Count:    len(g.Hosts),
+		Stdout:   g.Stdout,
+		Stderr:   g.Stderr,
+		ExitCode: g.ExitCode,
+	}
+	if g.Err != nil {
+		rec.Error = g.Err.Error()
+	}
+	return rec
+}
+
+func newRecord(r executor.Result) record {
+	rec := record{
+		Host:     r.Instance.Name,
+		Address:  r.Address,
+		State:    r.State.String(),
+		Message:  r.Message,
+		Stdout:   r.Stdout,
+		Stderr:   r.Stderr,
+		ExitCode: r.ExitCode,
+		Duration: r.Duration.String(),
+	}
+	if r.Err != nil {
+		rec.Error = r.Err.Error()
+	}
+	return rec
+}
+
+// reporter prints the results of a fan-out command in the requested format
+type reporter struct {
+	format string
+	quiet  bool
+	// aggregate prints every distinct result once along with the hosts that produced it
+	aggregate bool
+	// dir, if set, receives the output and metadata of every host in separate files
+	dir string
+	out io.Writer
+}
+
+// addOutputFlags adds the flags controlling how results are printed to cmd and its subcommands
+func addOutputFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP("output", "o", "text", "Output format: "+strings.Join(outputFormats, ", "))
+	cmd.PersistentFlags().BoolP("aggregate", "a", false, "Group the hosts with identical output or error and print each distinct result once")
+	cmd.PersistentFlags().String("output-dir", "", "Write the output of each host to <dir>/<host>.stdout, <host>.stderr and <host>.json")
+}
+
+func newReporter(cmd *cobra.Command) (*reporter, error) {
+	format, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return nil, err
+	}
+
+	valid := false
+	for _, f := range outputFormats {
+		valid = valid || f == format
+	}
+	if !valid {
+		return nil, fmt.Errorf("unknown output format \"%s\", use one of: %s", format, strings.Join(outputFormats, ", "))
+	}
+
+	aggregate, err := cmd.Flags().GetBool("aggregate")
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := cmd.Flags().GetString("output-dir")
+	if err != nil {
+		return nil, err
+	}
+
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("couldn't create output directory: %v", err)
+		}
+	}
+
+	return &reporter{format: format, aggregate: aggregate, dir: dir, out: os.Stdout}, nil
+}
+
+// result is called as soon as the result of a host is available
+func (p *reporter) result(r executor.Result) {
+	if p.dir != "" {
+		if err := p.save(r); err != nil {
+			resultLogger(r).Errorf("couldn't save output: %v", err)
+		}
+	}
+
+	switch p.format {
+	case "text":
+		log := resultLogger(r)
+		if r.Err != nil {
+			log.Error(r.Err.Error())
+		} else {
+			message := r.Message
+			if message == "" {
+				message = "Done"
+			}
+			log.WithField("state", r.State).Info(message)
+		}
+
+		if !p.quiet && !p.aggregate {
+			if r.Stdout != "" {
+				fmt.Fprintln(p.out, strings.TrimSuffix(r.Stdout, "\n"))
+			}
+			if r.Stderr != "" {
+				fmt.Fprintln(os.Stderr, strings.TrimSuffix(r.Stderr, "\n"))
+			}
+		}
+	case "ndjson":
+		if !p.aggregate {
+			p.line(r)
+		}
+	}
+}
+
+// save writes the output and metadata of the host to the output directory
+func (p *reporter) save(r executor.Result) error {
+	base := filepath.Join(p.dir, hostFile(r.Instance.Name))
+	if err := os.WriteFile(base+".stdout", []byte(r.Stdout), 0644); err != nil {
+		return err
+	}
+	if err := os.WriteFile(base+".stderr", []byte(r.Stderr), 0644); err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(newRecord(r), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(base+".json", append(b, '\n'), 0644)
+}
+
+// checkFiles fails when two instances would save their output to the same
+// files, e.g. hosts with the same name in different projects or providers
+func (p *reporter) checkFiles(instances []cloud.Instance) error {
+	if p.dir == "" {
+		return nil
+	}
+
+	seen := make(map[string]cloud.Instance, len(instances))
+	for _, i := range instances {
+		name := hostFile(i.Name)
+		if other, ok := seen[name]; ok {
+			return fmt.Errorf("instances %s and %s would both be saved to %s, narrow down the target, e.g. by provider or project", describeInstance(other), describeInstance(i), filepath.Join(p.dir, name+".*"))
+		}
+		seen[name] = i
+	}
+	return nil
+}
+
+// describeInstance names an instance along with where it comes from
+func describeInstance(i cloud.Instance) string {
+	var origin []string
+	for _, s := range []string{i.Provider, i.Project} {
+		if s != "" {
+			origin = append(origin, s)
+		}
+	}
+	if len(origin) == 0 {
+		return i.Name
+	}
+	return fmt.Sprintf("%s (%s)", i.Name, strings.Join(origin, "/"))
+}
+
+// hostFile turns a host name into something safe to use as a file name
+func hostFile(name string) string {
+	name = strings.NewReplacer("/", "_", "\\", "_").Replace(name)
+	if name == "" || name == "." || name == ".." {
+		name = "_" + name
+	}
+	return name
+}
+
+// line prints the result as a single line of JSON
+func (p *reporter) line(r executor.Result) {
+	b, err := json.Marshal(newRecord(r))
+	if err != nil {
+		resultLogger(r).Error(err.Error())
+		return
+	}
+	fmt.Fprintln(p.out, string(b))
+}
+
+// flush prints the results that are only printed once all of them are available
+func (p *reporter) flush(results []executor.Result) error {
+	if p.aggregate {
+		return p.flushGroups(executor.GroupResults(results))
+	}
+
+	records := make([]record, 0, len(results))
+	for _, r := range results {
+		records = append(records, newRecord(r))
+	}
+
+	switch p.format {
+	case "ndjson":
+		// skipped hosts never went through result
+		for _, r := range results {
+			if errors.Is(r.Err, executor.ErrSkipped) {
+				p.line(r)
+			}
+		}
+	case "json":
+		enc := json.NewEncoder(p.out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	case "yaml":
+		enc := yaml.NewEncoder(p.out)
+		enc.SetIndent(2)
+		if err := enc.Encode(records); err != nil {
+			return err
+		}
+		return enc.Close()
+	case "table":
+		w := tabwriter.NewWriter(p.out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "HOST\tADDRESS\tSTATE\tEXIT CODE\tDURATION\tOUTPUT")
+		for _, rec := range records {
+			exitCode := "-"
+			if rec.ExitCode != nil {
+				exitCode = fmt.Sprint(*rec.ExitCode)
+			}
+			output := rec.Stdout
+			if rec.Error != "" {
+				output = rec.Error
+			} else if output == "" {
+				output = rec.Message
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", rec.Host, rec.Address, rec.State, exitCode, rec.Duration, firstLine(output))
+		}
+		return w.Flush()
+	}
+	return nil
+}
+
+// firstLine returns the first line of s, marking it if s had more
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if n := strings.IndexByte(s, '\n'); n >= 0 {
+		return s[:n] + " ..."
+	}
+	return s
+}
+
+// flushGroups prints each group of hosts with identical results once
+func (p *reporter) flushGroups(groups []executor.Group) error {
+	records := make([]groupRecord, 0, len(groups))
+	for _, g := range groups {
+		records = append(records, newGroupRecord(g))
+	}
+
+	switch p.format {
+	case "text":
+		if p.quiet {
+			return nil
+		}
+		for _, rec := range records {
+			header := fmt.Sprintf("%s (%d)", strings.Join(rec.Hosts, ", "), rec.Count)
+			rule := strings.Repeat("-", 16)
+			fmt.Fprintf(p.out, "%s\n%s\n%s\n", rule, header, rule)
+			for _, s := range []string{rec.Stdout, rec.Stderr} {
+				if s != "" {
+					fmt.Fprintln(p.out, strings.TrimSuffix(s, "\n"))
+				}
+			}
+			if rec.Error != "" {
+				fmt.Fprintf(p.out, "error: %s\n", rec.Error)
+			}
+		}
+	case "ndjson":
+		for _, rec := range records {
+			b, err := json.Marshal(rec)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(p.out, string(b))
+		}
+	case "json":
+		enc := json.NewEncoder(p.out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	case "yaml":
+		enc := yaml.NewEncoder(p.out)
+		enc.SetIndent(2)
+		if err := enc.Encode(records); err != nil {
+			return err
+		}
+		return enc.Close()
+	case "table":
+		w := tabwriter.NewWriter(p.out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "COUNT\tHOSTS\tOUTPUT")
+		for _, rec := range records {
+			output := rec.Stdout
+			if rec.Error != "" {
+				output = rec.Error
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", rec.Count, strings.Join(rec.Hosts, ","), firstLine(output))
+		}
+		return w.Flush()
+	}
+	return nil
+}
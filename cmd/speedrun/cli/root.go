@@ -2,6 +2,7 @@ package cli
 
 import (
 	_ "embed"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,16 +10,36 @@ import (
 	"github.com/apex/log"
 	jsonhandler "github.com/apex/log/handlers/json"
 	texthandler "github.com/apex/log/handlers/text"
+	"github.com/dpogorzelski/speedrun/pkg/speedrun/cloud"
+	"github.com/dpogorzelski/speedrun/pkg/speedrun/executor"
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var cfgFile string
+var inventoryFile string
 var version string
 var commit string
 var date string
 
+// Exit codes, 0 means the operation succeeded on every instance
+const (
+	exitGeneralError   = 1
+	exitPartialFailure = 2
+	exitTotalFailure   = 3
+)
+
+// exitError makes the process exit with the given code
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
 //go:embed templates/root.tmpl
 var rootUsage string
 
@@ -36,7 +57,7 @@ func Execute() {
 
 	cobra.OnInitialize(initConfig)
 	rootCmd.SetUsageTemplate(rootUsage)
-	rootCmd.AddCommand(runCmd, serviceCmd, fileCmd, systemCmd)
+	rootCmd.AddCommand(runCmd, scriptCmd, serviceCmd, fileCmd, systemCmd, listCmd, inventoryCmd)
 
 	home, err := homedir.Dir()
 	if err != nil {
@@ -50,11 +71,27 @@ func Execute() {
 	rootCmd.PersistentFlags().StringP("loglevel", "l", "info", "Log level")
 	rootCmd.PersistentFlags().BoolP("json", "j", false, "Output logs in JSON format")
 	rootCmd.PersistentFlags().StringP("target", "t", "", "Fetch instances that match the target selection criteria")
+	rootCmd.PersistentFlags().Bool("strict", false, "Abort if the target expression can't be evaluated for any of the instances")
+	rootCmd.PersistentFlags().Bool("refresh", false, "Fetch the instance list from the providers instead of the cache")
+	rootCmd.PersistentFlags().StringVar(&inventoryFile, "inventory", "", "Path to a static inventory file, takes precedence over the configured provider")
 	rootCmd.PersistentFlags().Bool("insecure", false, "Skip server certificate verification")
 	rootCmd.PersistentFlags().String("ca", "ca.crt", "Path to the CA cert")
 	rootCmd.PersistentFlags().String("cert", "cert.crt", "Path to the client cert")
 	rootCmd.PersistentFlags().String("key", "key.key", "Path to the client key")
 	rootCmd.PersistentFlags().Bool("use-private-ip", false, "Connect to private IPs instead of public ones")
+	rootCmd.PersistentFlags().IntP("port", "p", cloud.DefaultPort, "Port portals listen on, overridden per instance by the inventory or the port label")
+	rootCmd.PersistentFlags().String("port-label", executor.DefaultPortLabel, "Label holding the portal port of an instance")
+	rootCmd.PersistentFlags().Int("concurrency", executor.DefaultConcurrency, "Maximum number of portals contacted at the same time")
+	rootCmd.PersistentFlags().Duration("dial-timeout", executor.DefaultDialTimeout, "Maximum time allowed to connect to a portal")
+	rootCmd.PersistentFlags().Duration("timeout", executor.DefaultTimeout, "Maximum time allowed for an operation on a portal, e.g. 30m for long running commands")
+	rootCmd.PersistentFlags().String("batch-size", "", "Roll out to this many instances at a time, either a count or a percentage, e.g. 10 or 25%")
+	rootCmd.PersistentFlags().Duration("batch-pause", 0, "Time to wait between two batches")
+	rootCmd.PersistentFlags().String("health-cmd", "", "Command that has to succeed on each instance of a batch before moving on to the next one")
+	rootCmd.PersistentFlags().String("health-service", "", "Service that has to be active on each instance of a batch before moving on to the next one")
+	rootCmd.PersistentFlags().String("canary", "", "Run on this many instances first, either a count or a percentage, and abort if any of them fails")
+	rootCmd.PersistentFlags().Int("max-failures", 0, "Stop contacting new instances once this many failed, 0 means no limit. Instances already contacted still run, see --strict-thresholds")
+	rootCmd.PersistentFlags().Float64("max-failure-rate", 0, "Stop contacting new instances once the percentage of failed ones reaches this value, 0 means no limit. Instances already contacted still run, see --strict-thresholds")
+	rootCmd.PersistentFlags().Bool("strict-thresholds", false, "Never overshoot --max-failures and --max-failure-rate by contacting no more instances at once than the failures still allowed, this overrides --concurrency")
 
 	viper.BindPFlag("logging.loglevel", rootCmd.PersistentFlags().Lookup("loglevel"))
 	viper.BindPFlag("logging.json", rootCmd.PersistentFlags().Lookup("json"))
@@ -62,12 +99,33 @@ func Execute() {
 	viper.BindPFlag("tls.ca", rootCmd.PersistentFlags().Lookup("ca"))
 	viper.BindPFlag("tls.cert", rootCmd.PersistentFlags().Lookup("cert"))
 	viper.BindPFlag("tls.key", rootCmd.PersistentFlags().Lookup("key"))
+	viper.BindPFlag("discovery.refresh", rootCmd.PersistentFlags().Lookup("refresh"))
+	viper.BindPFlag("discovery.strict", rootCmd.PersistentFlags().Lookup("strict"))
 	viper.BindPFlag("portal.use-private-ip", rootCmd.PersistentFlags().Lookup("use-private-ip"))
+	viper.BindPFlag("portal.port", rootCmd.PersistentFlags().Lookup("port"))
+	viper.BindPFlag("portal.port-label", rootCmd.PersistentFlags().Lookup("port-label"))
+	viper.BindPFlag("portal.concurrency", rootCmd.PersistentFlags().Lookup("concurrency"))
+	viper.BindPFlag("portal.dial-timeout", rootCmd.PersistentFlags().Lookup("dial-timeout"))
+	viper.BindPFlag("portal.timeout", rootCmd.PersistentFlags().Lookup("timeout"))
+	viper.BindPFlag("rollout.batch-size", rootCmd.PersistentFlags().Lookup("batch-size"))
+	viper.BindPFlag("rollout.batch-pause", rootCmd.PersistentFlags().Lookup("batch-pause"))
+	viper.BindPFlag("rollout.health-cmd", rootCmd.PersistentFlags().Lookup("health-cmd"))
+	viper.BindPFlag("rollout.health-service", rootCmd.PersistentFlags().Lookup("health-service"))
+	viper.BindPFlag("rollout.canary", rootCmd.PersistentFlags().Lookup("canary"))
+	viper.BindPFlag("rollout.max-failures", rootCmd.PersistentFlags().Lookup("max-failures"))
+	viper.BindPFlag("rollout.max-failure-rate", rootCmd.PersistentFlags().Lookup("max-failure-rate"))
+	viper.BindPFlag("rollout.strict-thresholds", rootCmd.PersistentFlags().Lookup("strict-thresholds"))
 
 	rootCmd.DisableSuggestions = false
 
 	if err := rootCmd.Execute(); err != nil {
 		log.Error(err.Error())
+
+		var eerr *exitError
+		if errors.As(err, &eerr) {
+			os.Exit(eerr.code)
+		}
+		os.Exit(exitGeneralError)
 	}
 }
 
@@ -77,10 +135,10 @@ func initConfig() {
 
 	json := viper.GetBool("logging.json")
 	if json {
-		handler := jsonhandler.New(os.Stdout)
+		handler := jsonhandler.New(os.Stderr)
 		log.SetHandler(handler)
 	} else {
-		handler := texthandler.New(os.Stdout)
+		handler := texthandler.New(os.Stderr)
 		log.SetHandler(handler)
 	}
 
@@ -88,6 +146,20 @@ func initConfig() {
 		log.Warnf("Couldn't read config at \"%s\", starting with default settings", viper.ConfigFileUsed())
 	}
 
+	if inventoryFile != "" {
+		// unlike the config file, a path given on the command line is
+		// relative to the working directory
+		path, err := filepath.Abs(inventoryFile)
+		if err != nil {
+			log.Fatalf("couldn't resolve inventory path: %s", err)
+			return
+		}
+		inventoryFile = path
+		viper.Set("discovery.providers", nil)
+		viper.Set("discovery.provider", "inventory")
+		viper.Set("inventory.path", inventoryFile)
+	}
+
 	lvl, err := log.ParseLevel(viper.GetString("logging.loglevel"))
 	if err != nil {
 		log.Fatalf("couldn't parse log level: %s (%s)", err, lvl)
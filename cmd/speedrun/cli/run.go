@@ -2,95 +2,194 @@ package cli
 
 import (
 	"context"
-	"crypto/tls"
-	"net"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
 	"strings"
 	"time"
 
-	"github.com/alitto/pond"
 	"github.com/dpogorzelski/speedrun/pkg/speedrun/cloud"
+	"github.com/dpogorzelski/speedrun/pkg/speedrun/executor"
 	portalpb "github.com/dpogorzelski/speedrun/proto/portal"
-	"storj.io/drpc/drpcconn"
 
-	"github.com/apex/log"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var runCmd = &cobra.Command{
 	Use:     "run <command to run>",
 	Short:   "Run a shell command on remote servers",
-	Example: "  speedrun run whoami\n  speedrun run whoami --target \"labels.foo = bar AND labels.environment = staging\"",
+	Example: "  speedrun run whoami\n  speedrun run whoami --target \"labels.foo = bar AND labels.environment = staging\"\n  speedrun run \"ps aux | grep nginx > /tmp/nginx.txt\"\n  speedrun run --exec -- touch \"file with spaces\"",
 	Args:    cobra.MinimumNArgs(1),
 	RunE:    run,
 }
 
+const defaultShell = "/bin/sh"
+
 func init() {
 	runCmd.SetUsageTemplate(usage)
 	runCmd.Flags().BoolP("quiet", "q", false, "Suppress command output")
+	runCmd.Flags().String("shell", defaultShell, "Shell used to run the command line")
+	runCmd.Flags().Bool("exec", false, "Execute the command with its arguments exactly as given instead of running it through a shell")
+	runCmd.Flags().Bool("stream", false, "Print the output of the command as it's produced, each line prefixed with the host name")
+	addCommandFlags(runCmd)
+	addOutputFlags(runCmd)
+}
+
+// addCommandFlags adds the flags setting the context commands run in
+func addCommandFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayP("env", "e", nil, "Environment variable to set for the command, as KEY=VALUE, can be repeated")
+	cmd.Flags().String("dir", "", "Working directory of the command")
+	cmd.Flags().StringP("user", "u", "", "User to run the command as, by name or uid")
+	cmd.Flags().String("group", "", "Group to run the command as, by name or gid, defaults to the primary group of --user")
+	cmd.Flags().String("stdin", "", "Local file whose content is piped to the standard input of the command")
+	cmd.Flags().Duration("command-timeout", 0, "Kill the command along with the processes it started if it's still running after this long")
 }
 
 func run(cmd *cobra.Command, args []string) error {
-	command := strings.Join(args, " ")
-	s := strings.Split(command, " ")
+	shell, err := cmd.Flags().GetString("shell")
+	if err != nil {
+		return err
+	}
+
+	execMode, err := cmd.Flags().GetBool("exec")
+	if err != nil {
+		return err
+	}
+
 	quiet, err := cmd.Flags().GetBool("quiet")
 	if err != nil {
 		return err
 	}
 
-	usePrivateIP := viper.GetBool("portal.use-private-ip")
+	stream, err := cmd.Flags().GetBool("stream")
+	if err != nil {
+		return err
+	}
 
-	tlsConfig, err := cloud.SetupTLS()
+	out, err := newReporter(cmd)
 	if err != nil {
 		return err
 	}
+	out.quiet = quiet
 
-	target, err := cmd.Flags().GetString("target")
+	if stream && (out.format != "text" || out.aggregate || out.dir != "") {
+		return errors.New("--stream can only be used with the text output, without --aggregate and --output-dir")
+	}
+
+	req := &portalpb.CommandRequest{Name: strings.Join(args, " "), Shell: shell}
+	if execMode {
+		req = &portalpb.CommandRequest{Name: args[0], Args: args[1:]}
+	}
+	if err := setCommandContext(cmd, req); err != nil {
+		return err
+	}
+
+	return runRequest(cmd, out, req, stream)
+}
+
+// runRequest runs the command on the target instances and reports the
+// results, with stream set the output is printed as it's produced
+func runRequest(cmd *cobra.Command, out *reporter, req *portalpb.CommandRequest, stream bool) error {
+	portals, err := targetInstances(cmd)
 	if err != nil {
 		return err
 	}
+	if err := out.checkFiles(portals); err != nil {
+		return err
+	}
 
-	portals, err := cloud.GetInstances(target)
+	fleet, err := newExecutor(len(portals))
 	if err != nil {
 		return err
 	}
 
-	pool := pond.New(1000, 10000)
-	for _, p := range portals {
-		portal := p
-		pool.Submit(func() {
-			fields := log.Fields{
-				"host":    portal.Name,
-				"address": portal.GetAddress(usePrivateIP),
-			}
-			log := log.WithFields(fields)
-
-			addr := net.JoinHostPort(portal.GetAddress(usePrivateIP), "1337")
-			rawconn, err := tls.Dial("tcp", addr, tlsConfig)
-			if err != nil {
-				log.Error(err.Error())
-				return
-			}
-
-			conn := drpcconn.New(rawconn)
-			defer conn.Close()
-
-			c := portalpb.NewDRPCPortalClient(conn)
-			ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
-			defer cancel()
-
-			r, err := c.RunCommand(ctx, &portalpb.CommandRequest{Name: s[0], Args: s[1:]})
-			if err != nil {
-				log.Error(err.Error())
-				return
-			}
-
-			log.WithField("state", r.GetState()).Info("Done")
-			if !quiet {
-				println(r.GetMessage())
-			}
-		})
-	}
-	pool.StopAndWait()
+	op := func(ctx context.Context, c portalpb.DRPCPortalClient, _ cloud.Instance) (*executor.Response, error) {
+		r, err := c.RunCommand(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return commandResponse(r)
+	}
+	if stream {
+		op = streamCommand(req, out.quiet)
+	}
+
+	// cancelling the context closes the connections which kills the remote commands
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	results := fleet.Run(ctx, portals, op, out.result)
+	if err := out.flush(results); err != nil {
+		return err
+	}
+	return checkResults(results)
+}
+
+// setCommandContext sets the environment, working directory, credentials,
+// stdin and timeout of the command from the flags
+func setCommandContext(cmd *cobra.Command, req *portalpb.CommandRequest) error {
+	env, err := cmd.Flags().GetStringArray("env")
+	if err != nil {
+		return err
+	}
+	for _, e := range env {
+		k, v, ok := strings.Cut(e, "=")
+		if !ok || k == "" {
+			return fmt.Errorf("invalid environment variable \"%s\", expected KEY=VALUE", e)
+		}
+		if req.Env == nil {
+			req.Env = map[string]string{}
+		}
+		req.Env[k] = v
+	}
+
+	if req.Dir, err = cmd.Flags().GetString("dir"); err != nil {
+		return err
+	}
+	if req.User, err = cmd.Flags().GetString("user"); err != nil {
+		return err
+	}
+	if req.Group, err = cmd.Flags().GetString("group"); err != nil {
+		return err
+	}
+
+	stdin, err := cmd.Flags().GetString("stdin")
+	if err != nil {
+		return err
+	}
+	if stdin != "" {
+		if req.Stdin, err = os.ReadFile(stdin); err != nil {
+			return fmt.Errorf("couldn't read stdin: %v", err)
+		}
+	}
+
+	timeout, err := cmd.Flags().GetDuration("command-timeout")
+	if err != nil {
+		return err
+	}
+	req.Timeout = int64(timeout)
 	return nil
 }
+
+// commandResponse turns the outcome of a command into a response, a command
+// that didn't exit with 0 is an error
+func commandResponse(r *portalpb.CommandResponse) (*executor.Response, error) {
+	exitCode := int(r.GetExitCode())
+	resp := &executor.Response{
+		State:    r.GetState(),
+		Stdout:   r.GetStdout(),
+		Stderr:   r.GetStderr(),
+		ExitCode: &exitCode,
+	}
+
+	switch {
+	case r.GetTimedOut():
+		return resp, fmt.Errorf("command timed out after %s", time.Duration(r.GetDuration()).Round(time.Millisecond))
+	case r.GetSignal() != "":
+		return resp, fmt.Errorf("command was terminated by signal: %s", r.GetSignal())
+	case exitCode != 0:
+		return resp, fmt.Errorf("command exited with code %d", exitCode)
+	}
+	return resp, nil
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"strings"
+
+	portalpb "github.com/dpogorzelski/speedrun/proto/portal"
+	"github.com/spf13/cobra"
+)
+
+var scriptCmd = &cobra.Command{
+	Use:     "script <path> [args...]",
+	Short:   "Run a local script on remote servers",
+	Example: "  speedrun script ./deploy.sh\n  speedrun script ./deploy.sh -- v1.2.3 --force\n  speedrun script ./check.py --interpreter \"python3 -u\"",
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    script,
+}
+
+func init() {
+	scriptCmd.SetUsageTemplate(usage)
+	scriptCmd.Flags().BoolP("quiet", "q", false, "Suppress script output")
+	scriptCmd.Flags().String("interpreter", "", "Interpreter to run the script with, taken from its shebang or "+defaultShell+" if not set")
+	addCommandFlags(scriptCmd)
+	addOutputFlags(scriptCmd)
+}
+
+func script(cmd *cobra.Command, args []string) error {
+	content, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	interpreter, err := cmd.Flags().GetString("interpreter")
+	if err != nil {
+		return err
+	}
+
+	quiet, err := cmd.Flags().GetBool("quiet")
+	if err != nil {
+		return err
+	}
+
+	out, err := newReporter(cmd)
+	if err != nil {
+		return err
+	}
+	out.quiet = quiet
+
+	req := &portalpb.CommandRequest{Script: content, Interpreter: strings.Fields(interpreter), Args: args[1:]}
+	if len(req.Interpreter) == 0 {
+		req.Interpreter = shebang(content)
+	}
+	if err := setCommandContext(cmd, req); err != nil {
+		return err
+	}
+
+	// the output is streamed unless it has to be collected first
+	stream := out.format == "text" && !out.aggregate && out.dir == ""
+	return runRequest(cmd, out, req, stream)
+}
+
+// shebang returns the interpreter named on the first line of the script,
+// e.g. [/usr/bin/env bash] for #!/usr/bin/env bash, or the default shell
+func shebang(script []byte) []string {
+	line, _ := bufio.NewReader(bytes.NewReader(script)).ReadString('\n')
+	if !strings.HasPrefix(line, "#!") {
+		return []string{defaultShell}
+	}
+
+	interpreter := strings.Fields(strings.TrimPrefix(line, "#!"))
+	if len(interpreter) == 0 {
+		return []string{defaultShell}
+	}
+	return interpreter
+}
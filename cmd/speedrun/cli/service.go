@@ -2,18 +2,13 @@ package cli
 
 import (
 	"context"
-	"crypto/tls"
-	"net"
+	"fmt"
 	"strings"
-	"time"
 
-	"github.com/alitto/pond"
-	"github.com/apex/log"
 	"github.com/dpogorzelski/speedrun/pkg/speedrun/cloud"
+	"github.com/dpogorzelski/speedrun/pkg/speedrun/executor"
 	portalpb "github.com/dpogorzelski/speedrun/proto/portal"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
-	"storj.io/drpc/drpcconn"
 )
 
 var serviceCmd = &cobra.Command{
@@ -56,6 +51,7 @@ var statusCmd = &cobra.Command{
 
 func init() {
 	serviceCmd.SetUsageTemplate(usage)
+	addOutputFlags(serviceCmd)
 	serviceCmd.AddCommand(restartCmd)
 	serviceCmd.AddCommand(startCmd)
 	serviceCmd.AddCommand(stopCmd)
@@ -63,80 +59,55 @@ func init() {
 }
 
 func action(cmd *cobra.Command, args []string) error {
-	usePrivateIP := viper.GetBool("portal.use-private-ip")
-
-	tlsConfig, err := cloud.SetupTLS()
+	out, err := newReporter(cmd)
 	if err != nil {
 		return err
 	}
 
-	target, err := cmd.Flags().GetString("target")
+	portals, err := targetInstances(cmd)
 	if err != nil {
 		return err
 	}
+	if err := out.checkFiles(portals); err != nil {
+		return err
+	}
 
-	portals, err := cloud.GetInstances(target)
+	fleet, err := newExecutor(len(portals))
 	if err != nil {
 		return err
 	}
 
-	pool := pond.New(1000, 10000)
-	for _, p := range portals {
-		portal := p
-		pool.Submit(func() {
-			fields := log.Fields{
-				"host":    portal.Name,
-				"address": portal.GetAddress(usePrivateIP),
-			}
-			log := log.WithFields(fields)
-
-			addr := net.JoinHostPort(portal.GetAddress(usePrivateIP), "1337")
-			rawconn, err := tls.Dial("tcp", addr, tlsConfig)
+	name := strings.Join(args, " ")
+	op := func(ctx context.Context, c portalpb.DRPCPortalClient, _ cloud.Instance) (*executor.Response, error) {
+		var r *portalpb.ServiceResponse
+		var err error
+
+		switch cmd.Name() {
+		case "restart":
+			r, err = c.ServiceRestart(ctx, &portalpb.ServiceRequest{Name: name})
+		case "start":
+			r, err = c.ServiceStart(ctx, &portalpb.ServiceRequest{Name: name})
+		case "stop":
+			r, err = c.ServiceStop(ctx, &portalpb.ServiceRequest{Name: name})
+		case "status":
+			s, err := c.ServiceStatus(ctx, &portalpb.ServiceRequest{Name: name})
 			if err != nil {
-				log.Error(err.Error())
-				return
-			}
-
-			conn := drpcconn.New(rawconn)
-			defer conn.Close()
-
-			c := portalpb.NewDRPCPortalClient(conn)
-			ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
-			defer cancel()
-
-			switch cmd.Name() {
-			case "restart":
-				r, err := c.ServiceRestart(ctx, &portalpb.ServiceRequest{Name: strings.Join(args, " ")})
-				if err != nil {
-					log.Error(err.Error())
-					return
-				}
-				log.WithField("state", r.GetState()).Info(r.GetMessage())
-			case "start":
-				r, err := c.ServiceStart(ctx, &portalpb.ServiceRequest{Name: strings.Join(args, " ")})
-				if err != nil {
-					log.Error(err.Error())
-					return
-				}
-				log.WithField("state", r.GetState()).Info(r.GetMessage())
-			case "stop":
-				r, err := c.ServiceStop(ctx, &portalpb.ServiceRequest{Name: strings.Join(args, " ")})
-				if err != nil {
-					log.Error(err.Error())
-					return
-				}
-				log.WithField("state", r.GetState()).Info(r.GetMessage())
-			case "status":
-				r, err := c.ServiceStatus(ctx, &portalpb.ServiceRequest{Name: strings.Join(args, " ")})
-				if err != nil {
-					log.Error(err.Error())
-					return
-				}
-				log.WithField("state", r.GetState()).Infof("Loadstate: \"%s\", Activestate: \"%s\", Substate: \"%s\"", r.GetLoadstate(), r.GetActivestate(), r.GetSubstate())
+				return nil, err
 			}
+			return &executor.Response{
+				State:  s.GetState(),
+				Stdout: fmt.Sprintf("Loadstate: \"%s\", Activestate: \"%s\", Substate: \"%s\"", s.GetLoadstate(), s.GetActivestate(), s.GetSubstate()),
+			}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &executor.Response{State: r.GetState(), Message: r.GetMessage()}, nil
+	}
 
-		})
+	results := fleet.Run(context.Background(), portals, op, out.result)
+	if err := out.flush(results); err != nil {
+		return err
 	}
-	pool.StopAndWait()
-	return nil
+	return checkResults(results)
 }
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/dpogorzelski/speedrun/pkg/speedrun/cloud"
+	"github.com/dpogorzelski/speedrun/pkg/speedrun/executor"
+	portalpb "github.com/dpogorzelski/speedrun/proto/portal"
+)
+
+// streamMu keeps the lines printed by different hosts from interleaving
+var streamMu sync.Mutex
+
+// streamCommand returns the operation running the command and printing its
+// output live, each line prefixed with the host name
+func streamCommand(req *portalpb.CommandRequest, quiet bool) executor.Operation {
+	return func(ctx context.Context, c portalpb.DRPCPortalClient, instance cloud.Instance) (*executor.Response, error) {
+		stream, err := c.RunCommandStream(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		defer stream.Close()
+
+		stdout := &prefixWriter{prefix: instance.Name + ": ", out: os.Stdout}
+		stderr := &prefixWriter{prefix: instance.Name + ": ", out: os.Stderr}
+		defer stdout.Flush()
+		defer stderr.Flush()
+
+		for {
+			chunk, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return nil, errors.New("stream ended before the command completed")
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			if r := chunk.GetResult(); r != nil {
+				return commandResponse(r)
+			}
+			if !quiet {
+				stdout.Write(chunk.GetStdout())
+				stderr.Write(chunk.GetStderr())
+			}
+		}
+	}
+}
+
+// prefixWriter prints complete lines prefixed with prefix, the last
+// incomplete line is held back until it's completed or flushed
+type prefixWriter struct {
+	prefix string
+	out    io.Writer
+	buf    []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	n := bytes.LastIndexByte(w.buf, '\n')
+	if n < 0 {
+		return len(p), nil
+	}
+
+	w.print(w.buf[:n+1])
+	w.buf = append(w.buf[:0], w.buf[n+1:]...)
+	return len(p), nil
+}
+
+// Flush prints what's left of the output
+func (w *prefixWriter) Flush() {
+	if len(w.buf) > 0 {
+		w.print(append(w.buf, '\n'))
+		w.buf = w.buf[:0]
+	}
+}
+
+func (w *prefixWriter) print(lines []byte) {
+	var b bytes.Buffer
+	for _, line := range bytes.SplitAfter(lines, []byte("\n")) {
+		if len(line) > 0 {
+			b.WriteString(w.prefix)
+			b.Write(line)
+		}
+	}
+
+	streamMu.Lock()
+	defer streamMu.Unlock()
+	w.out.Write(b.Bytes())
+}
@@ -2,17 +2,12 @@ package cli
 
 import (
 	"context"
-	"crypto/tls"
-	"net"
-	"time"
+	"fmt"
 
-	"github.com/alitto/pond"
-	"github.com/apex/log"
 	"github.com/dpogorzelski/speedrun/pkg/speedrun/cloud"
+	"github.com/dpogorzelski/speedrun/pkg/speedrun/executor"
 	portalpb "github.com/dpogorzelski/speedrun/proto/portal"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
-	"storj.io/drpc/drpcconn"
 )
 
 var systemCmd = &cobra.Command{
@@ -39,71 +34,52 @@ var shutdownCmd = &cobra.Command{
 
 func init() {
 	systemCmd.SetUsageTemplate(usage)
+	addOutputFlags(systemCmd)
 	systemCmd.AddCommand(rebootCmd)
 	systemCmd.AddCommand(shutdownCmd)
 }
 
 func systemAction(cmd *cobra.Command, _ []string) error {
-	usePrivateIP := viper.GetBool("portal.use-private-ip")
-
-	tlsConfig, err := cloud.SetupTLS()
+	out, err := newReporter(cmd)
 	if err != nil {
 		return err
 	}
 
-	target, err := cmd.Flags().GetString("target")
+	portals, err := targetInstances(cmd)
 	if err != nil {
 		return err
 	}
+	if err := out.checkFiles(portals); err != nil {
+		return err
+	}
 
-	portals, err := cloud.GetInstances(target)
+	fleet, err := newExecutor(len(portals))
 	if err != nil {
 		return err
 	}
 
-	pool := pond.New(1000, 10000)
-	for _, p := range portals {
-		portal := p
-		pool.Submit(func() {
-			fields := log.Fields{
-				"host":    portal.Name,
-				"address": portal.GetAddress(usePrivateIP),
+	op := func(ctx context.Context, c portalpb.DRPCPortalClient, _ cloud.Instance) (*executor.Response, error) {
+		switch cmd.Name() {
+		case "reboot":
+			r, err := c.SystemReboot(ctx, &portalpb.SystemRebootRequest{})
+			if err != nil {
+				return nil, err
 			}
-			log := log.WithFields(fields)
+			return &executor.Response{State: r.GetState(), Message: r.GetMessage()}, nil
 
-			addr := net.JoinHostPort(portal.GetAddress(usePrivateIP), "1337")
-			rawconn, err := tls.Dial("tcp", addr, tlsConfig)
+		case "shutdown":
+			r, err := c.SystemShutdown(ctx, &portalpb.SystemShutdownRequest{})
 			if err != nil {
-				log.Error(err.Error())
-				return
+				return nil, err
 			}
+			return &executor.Response{State: r.GetState(), Message: r.GetMessage()}, nil
+		}
+		return nil, fmt.Errorf("unknown system action \"%s\"", cmd.Name())
+	}
 
-			conn := drpcconn.New(rawconn)
-			defer conn.Close()
-
-			c := portalpb.NewDRPCPortalClient(conn)
-			ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
-			defer cancel()
-
-			switch cmd.Name() {
-			case "reboot":
-				r, err := c.SystemReboot(ctx, &portalpb.SystemRebootRequest{})
-				if err != nil {
-					log.Error(err.Error())
-					return
-				}
-				log.WithField("state", r.GetState()).Infof(r.GetMessage())
-
-			case "shutdown":
-				r, err := c.SystemShutdown(ctx, &portalpb.SystemShutdownRequest{})
-				if err != nil {
-					log.Error(err.Error())
-					return
-				}
-				log.WithField("state", r.GetState()).Infof(r.GetMessage())
-			}
-		})
+	results := fleet.Run(context.Background(), portals, op, out.result)
+	if err := out.flush(results); err != nil {
+		return err
 	}
-	pool.StopAndWait()
-	return nil
+	return checkResults(results)
 }
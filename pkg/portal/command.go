@@ -1,28 +1,217 @@
 package portal
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
 	"os/exec"
+	"sort"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/apex/log"
 
 	"github.com/dpogorzelski/speedrun/proto/portal"
 )
 
+// waitDelay is how long a command gets to release its output once it has
+// exited or has been killed, e.g. when it left a child running in the background
+const waitDelay = 5 * time.Second
+
+// RunCommand runs the command and returns its output and exit code. A command
+// that ran but exited with a non-zero code isn't an error, only failing to
+// start it is.
 func (s *Server) RunCommand(ctx context.Context, in *portal.CommandRequest) (*portal.CommandResponse, error) {
 	fields := log.Fields{
 		"context": "command",
 	}
 	log := log.WithFields(fields)
 
-	log.Debugf("Received command: %s %s", in.GetName(), in.GetArgs())
-	cmd := exec.Command(in.GetName(), in.GetArgs()...)
-	stdout, err := cmd.CombinedOutput()
+	log.Debugf("Received command: %s %s (shell: %q, script: %d bytes)", in.GetName(), in.GetArgs(), in.GetShell(), len(in.GetScript()))
+	var mu sync.Mutex
+	var stdout, stderr, combined bytes.Buffer
+	output := func(b *bytes.Buffer) io.Writer {
+		return &streamWriter{mu: &mu, send: func(p []byte) error {
+			b.Write(p)
+			combined.Write(p)
+			return nil
+		}}
+	}
+
+	resp, err := runCommand(ctx, in, output(&stdout), output(&stderr))
+	if err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+
+	resp.Stdout = stdout.String()
+	resp.Stderr = stderr.String()
+	// older clients only know about the combined output
+	resp.Message = strings.TrimSpace(combined.String())
+	return resp, nil
+}
+
+// RunCommandStream runs the command and sends its output as it's produced,
+// followed by its result. The command is killed if the client goes away.
+func (s *Server) RunCommandStream(in *portal.CommandRequest, stream portal.DRPCPortal_RunCommandStreamStream) error {
+	fields := log.Fields{
+		"context": "command",
+	}
+	log := log.WithFields(fields)
+
+	log.Debugf("Received streamed command: %s %s (shell: %q, script: %d bytes)", in.GetName(), in.GetArgs(), in.GetShell(), len(in.GetScript()))
+	var mu sync.Mutex
+	stdout := &streamWriter{mu: &mu, send: func(p []byte) error {
+		return stream.Send(&portal.CommandOutput{Stdout: p})
+	}}
+	stderr := &streamWriter{mu: &mu, send: func(p []byte) error {
+		return stream.Send(&portal.CommandOutput{Stderr: p})
+	}}
 
+	resp, err := runCommand(stream.Context(), in, stdout, stderr)
 	if err != nil {
 		log.Error(err.Error())
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	return stream.Send(&portal.CommandOutput{Result: resp})
+}
+
+// runCommand runs the command described by the request and returns its
+// outcome, the output is written to stdout and stderr
+func runCommand(ctx context.Context, in *portal.CommandRequest, stdout, stderr io.Writer) (*portal.CommandResponse, error) {
+	if in.GetTimeout() > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(in.GetTimeout()))
+		defer cancel()
+	}
+
+	var script string
+	if len(in.GetScript()) > 0 {
+		var err error
+		script, err = writeScript(in.GetScript())
+		if err != nil {
+			return nil, fmt.Errorf("couldn't write script: %v", err)
+		}
+		defer os.Remove(script)
+	}
+
+	cmd, err := command(ctx, in, script)
+	if err != nil {
+		return nil, err
+	}
+	if script != "" {
+		// the script has to be readable by the user it runs as
+		if err := setOwner(cmd, script); err != nil {
+			return nil, fmt.Errorf("couldn't change the owner of the script: %v", err)
+		}
+	}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	start := time.Now()
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) && !errors.Is(err, exec.ErrWaitDelay) {
 		return nil, err
 	}
-	return &portal.CommandResponse{Message: strings.TrimSpace(string(stdout))}, nil
+
+	resp := &portal.CommandResponse{
+		ExitCode: int32(cmd.ProcessState.ExitCode()),
+		Duration: int64(time.Since(start)),
+		TimedOut: errors.Is(ctx.Err(), context.DeadlineExceeded),
+	}
+	if status, ok := cmd.ProcessState.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		resp.Signal = status.Signal().String()
+	}
+	return resp, nil
+}
+
+// command returns the command described by the request, either the script
+// written to the given path, a command line run by a shell or an executable
+// with its arguments
+func command(ctx context.Context, in *portal.CommandRequest, script string) (*exec.Cmd, error) {
+	var cmd *exec.Cmd
+	switch interpreter := in.GetInterpreter(); {
+	case script != "" && len(interpreter) > 0:
+		args := append(append(append([]string{}, interpreter[1:]...), script), in.GetArgs()...)
+		cmd = exec.CommandContext(ctx, interpreter[0], args...)
+	case script != "":
+		cmd = exec.CommandContext(ctx, script, in.GetArgs()...)
+	case in.GetShell() != "":
+		args := append([]string{"-c", in.GetName(), in.GetShell()}, in.GetArgs()...)
+		cmd = exec.CommandContext(ctx, in.GetShell(), args...)
+	default:
+		cmd = exec.CommandContext(ctx, in.GetName(), in.GetArgs()...)
+	}
+
+	cmd.Dir = in.GetDir()
+	cmd.WaitDelay = waitDelay
+	if len(in.GetStdin()) > 0 {
+		cmd.Stdin = bytes.NewReader(in.GetStdin())
+	}
+
+	cmd.Env = os.Environ()
+	if err := setCredentials(cmd, in.GetUser(), in.GetGroup()); err != nil {
+		return nil, err
+	}
+	setProcessGroup(cmd)
+
+	keys := make([]string, 0, len(in.GetEnv()))
+	for k := range in.GetEnv() {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		cmd.Env = append(cmd.Env, k+"="+in.GetEnv()[k])
+	}
+	return cmd, nil
+}
+
+// writeScript writes the script to an executable temporary file and returns its path
+func writeScript(script []byte) (string, error) {
+	f, err := os.CreateTemp("", "speedrun-script-*")
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := f.Write(script); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", err
+	}
+	if err := f.Chmod(0700); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// streamWriter sends whatever is written to it, sends of the writers sharing
+// mu never overlap
+type streamWriter struct {
+	mu   *sync.Mutex
+	send func([]byte) error
+}
+
+func (w *streamWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.send(append([]byte(nil), p...)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
 }
@@ -0,0 +1,112 @@
+//go:build linux
+
+package portal
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"strconv"
+	"syscall"
+)
+
+// setCredentials makes the command run as the given user and group, the
+// primary group of the user is used if group is empty
+func setCredentials(cmd *exec.Cmd, name, group string) error {
+	if name == "" && group == "" {
+		return nil
+	}
+
+	cred := &syscall.Credential{Uid: uint32(os.Getuid()), Gid: uint32(os.Getgid())}
+	if name != "" {
+		lookup := user.Lookup
+		if isID(name) {
+			lookup = user.LookupId
+		}
+		u, err := lookup(name)
+		if err != nil {
+			return fmt.Errorf("couldn't find user %s: %v", name, err)
+		}
+
+		cred.Uid, err = parseID(u.Uid)
+		if err != nil {
+			return err
+		}
+		cred.Gid, err = parseID(u.Gid)
+		if err != nil {
+			return err
+		}
+
+		groups, err := u.GroupIds()
+		if err != nil {
+			return fmt.Errorf("couldn't list the groups of user %s: %v", name, err)
+		}
+		for _, g := range groups {
+			gid, err := parseID(g)
+			if err != nil {
+				return err
+			}
+			cred.Groups = append(cred.Groups, gid)
+		}
+
+		cmd.Env = append(cmd.Env, "HOME="+u.HomeDir, "USER="+u.Username, "LOGNAME="+u.Username)
+	}
+
+	if group != "" {
+		lookup := user.LookupGroup
+		if isID(group) {
+			lookup = user.LookupGroupId
+		}
+		g, err := lookup(group)
+		if err != nil {
+			return fmt.Errorf("couldn't find group %s: %v", group, err)
+		}
+
+		cred.Gid, err = parseID(g.Gid)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Credential = cred
+	return nil
+}
+
+// setOwner gives the file to the user and group the command runs as, if any
+func setOwner(cmd *exec.Cmd, path string) error {
+	if cmd.SysProcAttr == nil || cmd.SysProcAttr.Credential == nil {
+		return nil
+	}
+
+	cred := cmd.SysProcAttr.Credential
+	return os.Chown(path, int(cred.Uid), int(cred.Gid))
+}
+
+// setProcessGroup runs the command in its own process group so that the
+// processes it started are killed along with it when it's cancelled
+func setProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
+
+func isID(s string) bool {
+	_, err := strconv.ParseUint(s, 10, 32)
+	return err == nil
+}
+
+func parseID(id string) (uint32, error) {
+	n, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid id %s: %v", id, err)
+	}
+	return uint32(n), nil
+}
@@ -0,0 +1,22 @@
+//go:build !linux
+
+package portal
+
+import (
+	"errors"
+	"os/exec"
+)
+
+func setCredentials(cmd *exec.Cmd, name, group string) error {
+	if name != "" || group != "" {
+		return errors.New("running commands as another user or group is only supported on linux")
+	}
+	return nil
+}
+
+func setOwner(cmd *exec.Cmd, path string) error {
+	return nil
+}
+
+// setProcessGroup is a no-op, only the command itself is killed when it's cancelled
+func setProcessGroup(cmd *exec.Cmd) {}
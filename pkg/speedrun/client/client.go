@@ -0,0 +1,206 @@
+// Package client drives portals programmatically, it's what the speedrun CLI
+// does without the command line around it.
+//
+// Discover the instances, narrow them down with a target expression and
+// send them a request:
+//
+//	provider, err := cloud.NewProvider("inventory", cfg)
+//	if err != nil {
+//		return err
+//	}
+//
+//	instances, err := client.Discover(ctx, "labels.role == 'nginx'", provider)
+//	if err != nil {
+//		return err
+//	}
+//
+//	tlsConfig, err := cryptoutil.ClientTLSConfig("ca.crt", "speedrun.crt", "speedrun.key")
+//	if err != nil {
+//		return err
+//	}
+//
+//	c := client.New(executor.Config{TLS: tlsConfig})
+//	for _, r := range c.ServiceRestart(ctx, instances, &portalpb.ServiceRequest{Name: "nginx"}) {
+//		if r.Err != nil {
+//			fmt.Printf("%s: %v\n", r.Instance.Name, r.Err)
+//			continue
+//		}
+//		fmt.Printf("%s: %s\n", r.Instance.Name, r.Response.GetMessage())
+//	}
+//
+// Every method returns one result per instance, in the same order as the
+// instances it was given.
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/dpogorzelski/speedrun/pkg/speedrun/cloud"
+	"github.com/dpogorzelski/speedrun/pkg/speedrun/executor"
+	portalpb "github.com/dpogorzelski/speedrun/proto/portal"
+)
+
+// Client sends requests to the portals of many instances at once.
+type Client struct {
+	executor *executor.Executor
+}
+
+// Result is the outcome of a request sent to a single instance.
+type Result[T any] struct {
+	Instance cloud.Instance
+	// Address is the host:port the request was sent to.
+	Address  string
+	Response T
+	Duration time.Duration
+	Err      error
+}
+
+// New returns a client, see executor.Config for the available settings.
+func New(cfg executor.Config) *Client {
+	return &Client{executor: executor.New(cfg)}
+}
+
+// Discover returns the instances of the given providers that match target,
+// an empty target matches every instance. It fails if the target couldn't be
+// evaluated for any of the instances, e.g. because of a typo in a field name,
+// rather than returning a partial set.
+func Discover(ctx context.Context, target string, providers ...cloud.Provider) ([]cloud.Instance, error) {
+	instances, err := cloud.Discover(ctx, providers...)
+	if err != nil {
+		return nil, err
+	}
+
+	subset, evalErrs, err := cloud.Filter(instances, target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target expression: %w", err)
+	}
+	if len(evalErrs) > 0 {
+		return nil, fmt.Errorf("target expression couldn't be evaluated for %d out of %d instances, first on %s: %w", len(evalErrs), len(instances), evalErrs[0].Instance.Name, evalErrs[0])
+	}
+	return subset, nil
+}
+
+// Run performs a custom operation on every instance, onResult may be nil.
+func (c *Client) Run(ctx context.Context, instances []cloud.Instance, op executor.Operation, onResult func(executor.Result)) []executor.Result {
+	return c.executor.Run(ctx, instances, op, onResult)
+}
+
+func (c *Client) RunCommand(ctx context.Context, instances []cloud.Instance, req *portalpb.CommandRequest) []Result[*portalpb.CommandResponse] {
+	return call(ctx, c, instances, func(ctx context.Context, pc portalpb.DRPCPortalClient) (*portalpb.CommandResponse, error) {
+		return pc.RunCommand(ctx, req)
+	})
+}
+
+// RunCommandStream runs the command and passes its output to onOutput as it's
+// produced. onOutput is called concurrently for different instances, if it's
+// nil the output is discarded.
+func (c *Client) RunCommandStream(ctx context.Context, instances []cloud.Instance, req *portalpb.CommandRequest, onOutput func(cloud.Instance, *portalpb.CommandOutput)) []Result[*portalpb.CommandResponse] {
+	op := func(ctx context.Context, pc portalpb.DRPCPortalClient, instance cloud.Instance) (*executor.Response, error) {
+		stream, err := pc.RunCommandStream(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		defer stream.Close()
+
+		for {
+			chunk, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return nil, errors.New("stream ended before the command completed")
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			if r := chunk.GetResult(); r != nil {
+				return &executor.Response{Reply: r}, nil
+			}
+			if onOutput != nil {
+				onOutput(instance, chunk)
+			}
+		}
+	}
+	return collect[*portalpb.CommandResponse](c.executor.Run(ctx, instances, op, nil))
+}
+
+func (c *Client) ServiceRestart(ctx context.Context, instances []cloud.Instance, req *portalpb.ServiceRequest) []Result[*portalpb.ServiceResponse] {
+	return call(ctx, c, instances, func(ctx context.Context, pc portalpb.DRPCPortalClient) (*portalpb.ServiceResponse, error) {
+		return pc.ServiceRestart(ctx, req)
+	})
+}
+
+func (c *Client) ServiceStart(ctx context.Context, instances []cloud.Instance, req *portalpb.ServiceRequest) []Result[*portalpb.ServiceResponse] {
+	return call(ctx, c, instances, func(ctx context.Context, pc portalpb.DRPCPortalClient) (*portalpb.ServiceResponse, error) {
+		return pc.ServiceStart(ctx, req)
+	})
+}
+
+func (c *Client) ServiceStop(ctx context.Context, instances []cloud.Instance, req *portalpb.ServiceRequest) []Result[*portalpb.ServiceResponse] {
+	return call(ctx, c, instances, func(ctx context.Context, pc portalpb.DRPCPortalClient) (*portalpb.ServiceResponse, error) {
+		return pc.ServiceStop(ctx, req)
+	})
+}
+
+func (c *Client) ServiceStatus(ctx context.Context, instances []cloud.Instance, req *portalpb.ServiceRequest) []Result[*portalpb.ServiceStatusResponse] {
+	return call(ctx, c, instances, func(ctx context.Context, pc portalpb.DRPCPortalClient) (*portalpb.ServiceStatusResponse, error) {
+		return pc.ServiceStatus(ctx, req)
+	})
+}
+
+func (c *Client) FileRead(ctx context.Context, instances []cloud.Instance, req *portalpb.FileReadRequest) []Result[*portalpb.FileReadResponse] {
+	return call(ctx, c, instances, func(ctx context.Context, pc portalpb.DRPCPortalClient) (*portalpb.FileReadResponse, error) {
+		return pc.FileRead(ctx, req)
+	})
+}
+
+func (c *Client) FileCp(ctx context.Context, instances []cloud.Instance, req *portalpb.FileCpRequest) []Result[*portalpb.FileCpResponse] {
+	return call(ctx, c, instances, func(ctx context.Context, pc portalpb.DRPCPortalClient) (*portalpb.FileCpResponse, error) {
+		return pc.FileCp(ctx, req)
+	})
+}
+
+func (c *Client) FileChmod(ctx context.Context, instances []cloud.Instance, req *portalpb.FileChmodRequest) []Result[*portalpb.FileChmodResponse] {
+	return call(ctx, c, instances, func(ctx context.Context, pc portalpb.DRPCPortalClient) (*portalpb.FileChmodResponse, error) {
+		return pc.FileChmod(ctx, req)
+	})
+}
+
+func (c *Client) SystemReboot(ctx context.Context, instances []cloud.Instance, req *portalpb.SystemRebootRequest) []Result[*portalpb.SystemRebootResponse] {
+	return call(ctx, c, instances, func(ctx context.Context, pc portalpb.DRPCPortalClient) (*portalpb.SystemRebootResponse, error) {
+		return pc.SystemReboot(ctx, req)
+	})
+}
+
+func (c *Client) SystemShutdown(ctx context.Context, instances []cloud.Instance, req *portalpb.SystemShutdownRequest) []Result[*portalpb.SystemShutdownResponse] {
+	return call(ctx, c, instances, func(ctx context.Context, pc portalpb.DRPCPortalClient) (*portalpb.SystemShutdownResponse, error) {
+		return pc.SystemShutdown(ctx, req)
+	})
+}
+
+// call sends the same request to every instance and keeps the typed replies
+func call[T any](ctx context.Context, c *Client, instances []cloud.Instance, fn func(context.Context, portalpb.DRPCPortalClient) (T, error)) []Result[T] {
+	op := func(ctx context.Context, pc portalpb.DRPCPortalClient, _ cloud.Instance) (*executor.Response, error) {
+		reply, err := fn(ctx, pc)
+		if err != nil {
+			return nil, err
+		}
+		return &executor.Response{Reply: reply}, nil
+	}
+
+	return collect[T](c.executor.Run(ctx, instances, op, nil))
+}
+
+// collect keeps the typed replies of the results
+func collect[T any](results []executor.Result) []Result[T] {
+	out := make([]Result[T], len(results))
+	for n, r := range results {
+		out[n] = Result[T]{Instance: r.Instance, Address: r.Address, Duration: r.Duration, Err: r.Err}
+		if reply, ok := r.Reply.(T); ok {
+			out[n].Response = reply
+		}
+	}
+	return out
+}
@@ -0,0 +1,171 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dpogorzelski/speedrun/pkg/speedrun/client"
+	"github.com/dpogorzelski/speedrun/pkg/speedrun/cloud"
+	portalpb "github.com/dpogorzelski/speedrun/proto/portal"
+	"github.com/spf13/viper"
+)
+
+// newTestFleet returns a client along with two running portals and an
+// unreachable one in between
+func newTestFleet(t *testing.T) (*client.Client, []cloud.Instance) {
+	t.Helper()
+
+	var instances []cloud.Instance
+	for _, name := range []string{"first", "down", "last"} {
+		if name == "down" {
+			i, err := unreachable(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			instances = append(instances, i)
+			continue
+		}
+
+		i, stop, err := startPortal(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(stop)
+		instances = append(instances, i)
+	}
+
+	cfg, err := testConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client.New(cfg), instances
+}
+
+// checkOrder fails if the results aren't in the order of the instances or if
+// only the unreachable instance didn't fail
+func checkOrder[T any](t *testing.T, instances []cloud.Instance, results []client.Result[T]) {
+	t.Helper()
+
+	if len(results) != len(instances) {
+		t.Fatalf("expected %d results, got %d", len(instances), len(results))
+	}
+	for n, r := range results {
+		if r.Instance.Name != instances[n].Name {
+			t.Errorf("result #%d: expected host %s, got %s", n, instances[n].Name, r.Instance.Name)
+		}
+		if r.Instance.Name == "down" && r.Err == nil {
+			t.Errorf("expected an error for the unreachable host")
+		}
+		if r.Instance.Name != "down" && r.Err != nil {
+			t.Errorf("%s: unexpected error: %v", r.Instance.Name, r.Err)
+		}
+	}
+}
+
+func TestRunCommand(t *testing.T) {
+	c, instances := newTestFleet(t)
+
+	results := c.RunCommand(context.Background(), instances, &portalpb.CommandRequest{Name: "echo hello; echo oops >&2; exit 3", Shell: "/bin/sh"})
+	checkOrder(t, instances, results)
+
+	for _, r := range results {
+		if r.Err != nil {
+			continue
+		}
+		if r.Response.GetStdout() != "hello\n" || r.Response.GetStderr() != "oops\n" || r.Response.GetExitCode() != 3 {
+			t.Errorf("%s: expected the output and exit code of the command, got %q, %q and %d", r.Instance.Name, r.Response.GetStdout(), r.Response.GetStderr(), r.Response.GetExitCode())
+		}
+		// older clients print the message as the output of the command
+		if r.Response.GetMessage() != "hello\noops" {
+			t.Errorf("%s: expected the message to carry the combined output, got %q", r.Instance.Name, r.Response.GetMessage())
+		}
+	}
+}
+
+func TestRunCommandTimeout(t *testing.T) {
+	c, instances := newTestFleet(t)
+
+	req := &portalpb.CommandRequest{Name: "sleep 5", Shell: "/bin/sh", Timeout: int64(100 * time.Millisecond)}
+	results := c.RunCommand(context.Background(), instances[:1], req)
+	if results[0].Err != nil {
+		t.Fatal(results[0].Err)
+	}
+	if r := results[0].Response; !r.GetTimedOut() || r.GetSignal() == "" {
+		t.Errorf("expected the command to be killed for timing out, got: %v", r)
+	}
+}
+
+func TestServiceStatus(t *testing.T) {
+	c, instances := newTestFleet(t)
+
+	results := c.ServiceStatus(context.Background(), instances, &portalpb.ServiceRequest{Name: "nginx"})
+	checkOrder(t, instances, results)
+
+	for _, r := range results {
+		if r.Err == nil && r.Response.GetActivestate() != "active" {
+			t.Errorf("%s: expected an active service, got %q", r.Instance.Name, r.Response.GetActivestate())
+		}
+	}
+}
+
+func TestFileRead(t *testing.T) {
+	c, instances := newTestFleet(t)
+
+	path := filepath.Join(t.TempDir(), "motd")
+	if err := os.WriteFile(path, []byte("welcome"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	results := c.FileRead(context.Background(), instances, &portalpb.FileReadRequest{Path: path})
+	checkOrder(t, instances, results)
+
+	for _, r := range results {
+		if r.Err == nil && r.Response.GetContent() != "welcome" {
+			t.Errorf("%s: expected the content of the file, got %q", r.Instance.Name, r.Response.GetContent())
+		}
+	}
+
+	results = c.FileRead(context.Background(), instances[:1], &portalpb.FileReadRequest{Path: filepath.Join(t.TempDir(), "missing")})
+	if results[0].Err == nil {
+		t.Errorf("expected an error reading a missing file")
+	}
+}
+
+func TestDiscoverEvalErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inventory.toml")
+	inventory := `
+[[hosts]]
+  name = "numbered"
+  private-address = "10.0.0.1"
+  labels = { rack = "1" }
+
+[[hosts]]
+  name = "named"
+  private-address = "10.0.0.2"
+  labels = { rack = "a" }
+`
+	if err := os.WriteFile(path, []byte(inventory), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := viper.New()
+	cfg.Set("path", path)
+	provider, err := cloud.NewProvider("inventory", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	instances, err := client.Discover(context.Background(), "int(labels.rack) == 1", provider)
+	if err == nil {
+		t.Fatalf("expected an error instead of a partial set, got %d instances", len(instances))
+	}
+
+	var evalErr *cloud.EvalError
+	if !errors.As(err, &evalErr) || evalErr.Instance.Name != "named" {
+		t.Errorf("expected the evaluation error of host named, got: %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dpogorzelski/speedrun/pkg/speedrun/client"
+	"github.com/dpogorzelski/speedrun/pkg/speedrun/cloud"
+	"github.com/dpogorzelski/speedrun/pkg/speedrun/executor"
+	portalpb "github.com/dpogorzelski/speedrun/proto/portal"
+	"github.com/spf13/viper"
+)
+
+func ExampleDiscover() {
+	f, err := os.CreateTemp("", "inventory-*.toml")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer os.Remove(f.Name())
+
+	f.WriteString(`
+[[hosts]]
+  name = "web-1"
+  private-address = "10.0.0.1"
+  labels = { role = "web" }
+
+[[hosts]]
+  name = "db-1"
+  private-address = "10.0.0.2"
+  labels = { role = "db" }
+`)
+	f.Close()
+
+	cfg := viper.New()
+	cfg.Set("path", f.Name())
+	provider, err := cloud.NewProvider("inventory", cfg)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	instances, err := client.Discover(context.Background(), "labels.role == 'web'", provider)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	for _, i := range instances {
+		fmt.Println(i.Name, i.PrivateAddress)
+	}
+	// Output: web-1 10.0.0.1
+}
+
+func ExampleClient_RunCommand() {
+	instance, stop, err := startPortal("web-1")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer stop()
+
+	cfg, err := testConfig()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	c := client.New(cfg)
+	req := &portalpb.CommandRequest{Name: "echo hello from $0", Shell: "/bin/sh"}
+	for _, r := range c.RunCommand(context.Background(), []cloud.Instance{instance}, req) {
+		if r.Err != nil {
+			fmt.Printf("%s: %v\n", r.Instance.Name, r.Err)
+			continue
+		}
+		fmt.Printf("%s: %s", r.Instance.Name, r.Response.GetStdout())
+	}
+	// Output: web-1: hello from /bin/sh
+}
+
+func ExampleClient_RunCommandStream() {
+	instance, stop, err := startPortal("web-1")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer stop()
+
+	cfg, err := testConfig()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	c := client.New(cfg)
+	req := &portalpb.CommandRequest{Name: "echo one; echo two", Shell: "/bin/sh"}
+	var output strings.Builder
+	results := c.RunCommandStream(context.Background(), []cloud.Instance{instance}, req, func(_ cloud.Instance, chunk *portalpb.CommandOutput) {
+		// onOutput is called concurrently when there are several instances
+		output.Write(chunk.GetStdout())
+	})
+
+	fmt.Print(output.String())
+	fmt.Println("exit code:", results[0].Response.GetExitCode())
+	// Output:
+	// one
+	// two
+	// exit code: 0
+}
+
+func ExampleClient_Run() {
+	instance, stop, err := startPortal("web-1")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer stop()
+
+	cfg, err := testConfig()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	// a custom operation can send several requests over the same connection
+	op := func(ctx context.Context, pc portalpb.DRPCPortalClient, _ cloud.Instance) (*executor.Response, error) {
+		status, err := pc.ServiceStatus(ctx, &portalpb.ServiceRequest{Name: "nginx"})
+		if err != nil {
+			return nil, err
+		}
+		if status.GetActivestate() == "active" {
+			return &executor.Response{Message: "nginx is already running"}, nil
+		}
+
+		if _, err := pc.ServiceStart(ctx, &portalpb.ServiceRequest{Name: "nginx"}); err != nil {
+			return nil, err
+		}
+		return &executor.Response{State: portalpb.State_CHANGED, Message: "nginx started"}, nil
+	}
+
+	c := client.New(cfg)
+	for _, r := range c.Run(context.Background(), []cloud.Instance{instance}, op, nil) {
+		fmt.Printf("%s: %s\n", r.Instance.Name, r.Message)
+	}
+	// Output: web-1: nginx is already running
+}
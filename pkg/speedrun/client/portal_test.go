@@ -0,0 +1,83 @@
+package client_test
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+
+	"github.com/dpogorzelski/speedrun/pkg/common/cryptoutil"
+	"github.com/dpogorzelski/speedrun/pkg/portal"
+	"github.com/dpogorzelski/speedrun/pkg/speedrun/cloud"
+	"github.com/dpogorzelski/speedrun/pkg/speedrun/executor"
+	portalpb "github.com/dpogorzelski/speedrun/proto/portal"
+	"storj.io/drpc/drpcmux"
+	"storj.io/drpc/drpcserver"
+)
+
+// testPortal is a portal whose service status doesn't depend on systemd
+// being available on the machine running the tests
+type testPortal struct {
+	portal.Server
+}
+
+func (p *testPortal) ServiceStatus(_ context.Context, in *portalpb.ServiceRequest) (*portalpb.ServiceStatusResponse, error) {
+	return &portalpb.ServiceStatusResponse{
+		State:       portalpb.State_UNKNOWN,
+		Loadstate:   "loaded",
+		Activestate: "active",
+		Substate:    "running",
+	}, nil
+}
+
+// startPortal serves a portal on a loopback TLS listener and returns the
+// instance pointing at it along with the function stopping it
+func startPortal(name string) (cloud.Instance, func(), error) {
+	tlsConfig, err := cryptoutil.InsecureTLSConfig()
+	if err != nil {
+		return cloud.Instance{}, nil, err
+	}
+
+	m := drpcmux.New()
+	if err := portalpb.DRPCRegisterPortal(m, &testPortal{}); err != nil {
+		return cloud.Instance{}, nil, err
+	}
+
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", tlsConfig)
+	if err != nil {
+		return cloud.Instance{}, nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		drpcserver.New(m).Serve(ctx, lis)
+	}()
+
+	instance := cloud.Instance{
+		Name:           name,
+		PrivateAddress: "127.0.0.1",
+		Port:           lis.Addr().(*net.TCPAddr).Port,
+	}
+	return instance, func() { cancel(); <-done }, nil
+}
+
+// unreachable returns an instance no portal listens for
+func unreachable(name string) (cloud.Instance, error) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return cloud.Instance{}, err
+	}
+	defer lis.Close()
+
+	return cloud.Instance{Name: name, PrivateAddress: "127.0.0.1", Port: lis.Addr().(*net.TCPAddr).Port}, nil
+}
+
+// testConfig returns the settings of a client talking to test portals
+func testConfig() (executor.Config, error) {
+	tlsConfig, err := cryptoutil.InsecureTLSConfig()
+	if err != nil {
+		return executor.Config{}, err
+	}
+	return executor.Config{TLS: tlsConfig, UsePrivateIP: true}, nil
+}
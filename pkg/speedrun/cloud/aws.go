@@ -0,0 +1,135 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	Register("aws", func() Provider { return &AWSClient{} })
+}
+
+// AWSClient discovers EC2 instances across one or more regions.
+type AWSClient struct {
+	states []string
+	// regions keeps the configured order so that instances are always listed
+	// the same way
+	regions []awsRegion
+}
+
+type awsRegion struct {
+	name   string
+	client *ec2.Client
+}
+
+func (c *AWSClient) Name() string {
+	return "aws"
+}
+
+func (c *AWSClient) Schema() []Option {
+	return []Option{
+		{Key: "regions", Description: "list of AWS regions to fetch instances from", Required: true},
+		{Key: "profile", Description: "named profile from the shared AWS config"},
+		{Key: "states", Description: "instance states to list, e.g. [\"running\", \"stopped\"], an empty list lists every state", Default: []string{"running"}},
+		{Key: "endpoint", Description: "override for the EC2 API endpoint, e.g. a local EC2-compatible stand-in"},
+	}
+}
+
+func (c *AWSClient) Configure(cfg *viper.Viper) error {
+	regions := cfg.GetStringSlice("regions")
+	if len(regions) == 0 {
+		return fmt.Errorf("no AWS regions configured")
+	}
+
+	var opts []func(*config.LoadOptions) error
+	if profile := cfg.GetString("profile"); profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(profile))
+	}
+
+	awsCfg, err := config.LoadDefaultConfig(context.Background(), opts...)
+	if err != nil {
+		return fmt.Errorf("couldn't initialize AWS client: %v", err)
+	}
+
+	c.states = cfg.GetStringSlice("states")
+
+	endpoint := cfg.GetString("endpoint")
+	c.regions = make([]awsRegion, 0, len(regions))
+	for _, region := range regions {
+		region := region
+		client := ec2.NewFromConfig(awsCfg, func(o *ec2.Options) {
+			o.Region = region
+			if endpoint != "" {
+				o.EndpointResolver = ec2.EndpointResolverFromURL(endpoint)
+			}
+		})
+		c.regions = append(c.regions, awsRegion{name: region, client: client})
+	}
+	return nil
+}
+
+// GetInstances returns the instances in the configured states found in the
+// configured regions
+func (c *AWSClient) GetInstances(ctx context.Context) ([]Instance, error) {
+	instances := []Instance{}
+	input := &ec2.DescribeInstancesInput{}
+	if len(c.states) > 0 {
+		input.Filters = []types.Filter{
+			{Name: aws.String("instance-state-name"), Values: c.states},
+		}
+	}
+
+	for _, region := range c.regions {
+		paginator := ec2.NewDescribeInstancesPaginator(region.client, input)
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't list instances in %s: %v", region.name, err)
+			}
+
+			for _, reservation := range page.Reservations {
+				for _, instance := range reservation.Instances {
+					i := ec2Instance(instance)
+					i.Region = region.name
+					instances = append(instances, i)
+				}
+			}
+		}
+	}
+
+	return instances, nil
+}
+
+// ec2Instance converts an EC2 instance, its tags become labels and the
+// "Name" tag, when present, is used as the instance name.
+func ec2Instance(instance types.Instance) Instance {
+	i := Instance{
+		Name:           aws.ToString(instance.InstanceId),
+		PublicAddress:  aws.ToString(instance.PublicIpAddress),
+		PrivateAddress: aws.ToString(instance.PrivateIpAddress),
+		MachineType:    string(instance.InstanceType),
+		CreatedAt:      aws.ToTime(instance.LaunchTime),
+		Labels:         make(map[string]string, len(instance.Tags)),
+	}
+	if instance.Placement != nil {
+		i.Zone = aws.ToString(instance.Placement.AvailabilityZone)
+	}
+	if instance.State != nil {
+		i.Status = string(instance.State.Name)
+	}
+
+	for _, tag := range instance.Tags {
+		i.Labels[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	if name := i.Labels["Name"]; name != "" {
+		i.Name = name
+	}
+
+	return i
+}
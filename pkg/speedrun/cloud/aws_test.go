@@ -0,0 +1,155 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+// credentialRegion extracts the region from the credential scope of a
+// signed request
+var credentialRegion = regexp.MustCompile(`Credential=[^/]+/[^/]+/([^/]+)/`)
+
+// fakeEC2 serves the pages of DescribeInstances for each region, keyed by
+// page token, the first page has an empty token. Pages that aren't found fail
+// and so do requests that don't filter on the given instance states. Clients
+// are given static credentials to sign their requests with.
+func fakeEC2(t *testing.T, states []string, pages map[string]map[string]string) *httptest.Server {
+	t.Helper()
+
+	// keep the local AWS configuration out of the way
+	t.Setenv("AWS_CONFIG_FILE", "/nonexistent")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", "/nonexistent")
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDTEST")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+
+	return newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("Action") != "DescribeInstances" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		var filter []string
+		if r.PostForm.Get("Filter.1.Name") == "instance-state-name" {
+			for n := 1; r.PostForm.Has(fmt.Sprintf("Filter.1.Value.%d", n)); n++ {
+				filter = append(filter, r.PostForm.Get(fmt.Sprintf("Filter.1.Value.%d", n)))
+			}
+		}
+		if !reflect.DeepEqual(filter, states) {
+			t.Errorf("expected instances in states %v to be requested, got: %v", states, r.PostForm)
+		}
+
+		m := credentialRegion.FindStringSubmatch(r.Header.Get("Authorization"))
+		if m == nil {
+			http.Error(w, "unsigned request", http.StatusForbidden)
+			return
+		}
+
+		page, ok := pages[m[1]][r.PostForm.Get("NextToken")]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `<Response><Errors><Error><Code>InvalidParameterValue</Code><Message>invalid token</Message></Error></Errors><RequestID>1</RequestID></Response>`)
+			return
+		}
+		w.Header().Set("Content-Type", "text/xml")
+		fmt.Fprintf(w, `<DescribeInstancesResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/"><requestId>1</requestId>%s</DescribeInstancesResponse>`, page)
+	})
+}
+
+func TestAWSClientGetInstances(t *testing.T) {
+	srv := fakeEC2(t, []string{"running"}, map[string]map[string]string{
+		"eu-west-1": {
+			"": `<reservationSet><item><reservationId>r-1</reservationId><instancesSet>
+				<item>
+					<instanceId>i-1</instanceId>
+					<instanceType>t3.micro</instanceType>
+					<privateIpAddress>10.0.0.1</privateIpAddress>
+					<ipAddress>203.0.113.1</ipAddress>
+					<placement><availabilityZone>eu-west-1a</availabilityZone></placement>
+					<instanceState><code>16</code><name>running</name></instanceState>
+					<tagSet>
+						<item><key>Name</key><value>web-1</value></item>
+						<item><key>role</key><value>web</value></item>
+					</tagSet>
+				</item>
+			</instancesSet></item></reservationSet>
+			<nextToken>page2</nextToken>`,
+			"page2": `<reservationSet><item><reservationId>r-2</reservationId><instancesSet>
+				<item>
+					<instanceId>i-2</instanceId>
+					<instanceType>t3.small</instanceType>
+					<privateIpAddress>10.0.0.2</privateIpAddress>
+					<instanceState><code>16</code><name>running</name></instanceState>
+					<tagSet><item><key>role</key><value>db</value></item></tagSet>
+				</item>
+			</instancesSet></item></reservationSet>`,
+		},
+		"us-east-1": {
+			"": `<reservationSet><item><reservationId>r-3</reservationId><instancesSet>
+				<item>
+					<instanceId>i-3</instanceId>
+					<privateIpAddress>10.1.0.1</privateIpAddress>
+					<instanceState><code>16</code><name>running</name></instanceState>
+				</item>
+			</instancesSet></item></reservationSet>`,
+		},
+	})
+
+	// regions are listed in the configured order, not alphabetically
+	p := newTestProvider(t, "aws", map[string]interface{}{"endpoint": srv.URL}, map[string]interface{}{"regions": []string{"us-east-1", "eu-west-1"}})
+	instances, err := p.GetInstances(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Instance{
+		{Name: "i-3", PrivateAddress: "10.1.0.1", Region: "us-east-1", Status: "running", Labels: map[string]string{}},
+		{Name: "web-1", PrivateAddress: "10.0.0.1", PublicAddress: "203.0.113.1", Region: "eu-west-1", Zone: "eu-west-1a", MachineType: "t3.micro", Status: "running", Labels: map[string]string{"Name": "web-1", "role": "web"}},
+		{Name: "i-2", PrivateAddress: "10.0.0.2", Region: "eu-west-1", MachineType: "t3.small", Status: "running", Labels: map[string]string{"role": "db"}},
+	}
+	if !reflect.DeepEqual(instances, want) {
+		t.Errorf("unexpected instances\ngot:  %+v\nwant: %+v", instances, want)
+	}
+}
+
+func TestAWSClientGetInstancesPageError(t *testing.T) {
+	srv := fakeEC2(t, []string{"running"}, map[string]map[string]string{
+		"eu-west-1": {
+			"": `<reservationSet/><nextToken>page2</nextToken>`,
+		},
+	})
+
+	p := newTestProvider(t, "aws", map[string]interface{}{"endpoint": srv.URL}, map[string]interface{}{"regions": []string{"eu-west-1"}})
+	if _, err := p.GetInstances(context.Background()); err == nil {
+		t.Fatal("expected the error of the second page to be returned")
+	}
+}
+
+func TestAWSClientGetInstancesStates(t *testing.T) {
+	tests := []struct {
+		name   string
+		states []string
+		filter []string
+	}{
+		{name: "stopped too", states: []string{"running", "stopped"}, filter: []string{"running", "stopped"}},
+		{name: "every state", states: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := fakeEC2(t, tt.filter, map[string]map[string]string{
+				"eu-west-1": {"": `<reservationSet/>`},
+			})
+
+			p := newTestProvider(t, "aws", map[string]interface{}{"endpoint": srv.URL}, map[string]interface{}{"regions": []string{"eu-west-1"}, "states": tt.states})
+			if _, err := p.GetInstances(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
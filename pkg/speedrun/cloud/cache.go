@@ -0,0 +1,94 @@
+package cloud
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/spf13/viper"
+)
+
+type cacheEntry struct {
+	Provider  string     `json:"provider"`
+	FetchedAt time.Time  `json:"fetched_at"`
+	Instances []Instance `json:"instances"`
+}
+
+// cacheKey identifies a source by its name and a digest of its settings so
+// that changing e.g. the list of projects doesn't serve stale results.
+func cacheKey(name string, settings map[string]interface{}) string {
+	b, _ := json.Marshal(settings)
+	sum := sha256.Sum256(b)
+	return fmt.Sprintf("%s-%s", name, hex.EncodeToString(sum[:])[:12])
+}
+
+func cacheDir() (string, error) {
+	if dir := viper.GetString("discovery.cache-dir"); dir != "" {
+		return homedir.Expand(dir)
+	}
+
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".speedrun", "cache"), nil
+}
+
+// readCache returns the instances cached for the source if they're younger than ttl
+func readCache(s source, ttl time.Duration) ([]Instance, bool) {
+	dir, err := cacheDir()
+	if err != nil {
+		return nil, false
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, s.key+".json"))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(b, &entry); err != nil {
+		return nil, false
+	}
+
+	if time.Since(entry.FetchedAt) > ttl {
+		return nil, false
+	}
+	return entry.Instances, true
+}
+
+func writeCache(s source, instances []Instance) error {
+	dir, err := cacheDir()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(cacheEntry{Provider: s.name, FetchedAt: time.Now(), Instances: instances})
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, s.key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(dir, s.key+".json"))
+}
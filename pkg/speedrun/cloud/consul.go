@@ -0,0 +1,184 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alitto/pond"
+	"github.com/spf13/viper"
+)
+
+// consulConcurrency is the maximum number of services looked up at the same time
+const consulConcurrency = 16
+
+func init() {
+	Register("consul", func() Provider { return &ConsulClient{} })
+}
+
+// ConsulClient discovers the nodes registered in the Consul catalog.
+type ConsulClient struct {
+	address    string
+	datacenter string
+	token      string
+	timeout    time.Duration
+	httpClient *http.Client
+}
+
+type consulNode struct {
+	Node            string
+	Address         string
+	Datacenter      string
+	TaggedAddresses map[string]string
+	Meta            map[string]string
+}
+
+type consulServiceNode struct {
+	Node        string
+	ServiceName string
+}
+
+func (c *ConsulClient) Name() string {
+	return "consul"
+}
+
+func (c *ConsulClient) Schema() []Option {
+	return []Option{
+		{Key: "address", Description: "address of the Consul HTTP API", Default: "http://127.0.0.1:8500"},
+		{Key: "datacenter", Description: "datacenter to query, defaults to the one of the queried agent"},
+		{Key: "token", Description: "ACL token used to query the catalog"},
+		{Key: "timeout", Description: "maximum time allowed to query the catalog", Default: "30s"},
+	}
+}
+
+func (c *ConsulClient) Configure(cfg *viper.Viper) error {
+	c.address = cfg.GetString("address")
+	if addr := os.Getenv("CONSUL_HTTP_ADDR"); addr != "" && !cfg.IsSet("address") {
+		c.address = addr
+	}
+	if !strings.Contains(c.address, "://") {
+		c.address = "http://" + c.address
+	}
+
+	c.token = cfg.GetString("token")
+	if c.token == "" {
+		c.token = os.Getenv("CONSUL_HTTP_TOKEN")
+	}
+
+	c.datacenter = cfg.GetString("datacenter")
+	c.timeout = cfg.GetDuration("timeout")
+	c.httpClient = http.DefaultClient
+	return nil
+}
+
+// GetInstances returns the catalog nodes along with the services they provide
+func (c *ConsulClient) GetInstances(ctx context.Context) ([]Instance, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	var nodes []consulNode
+	if err := c.get(ctx, "/v1/catalog/nodes", &nodes); err != nil {
+		return nil, err
+	}
+
+	var catalog map[string][]string
+	if err := c.get(ctx, "/v1/catalog/services", &catalog); err != nil {
+		return nil, err
+	}
+
+	services, err := c.services(ctx, catalog)
+	if err != nil {
+		return nil, err
+	}
+
+	instances := make([]Instance, 0, len(nodes))
+	for _, node := range nodes {
+		i := Instance{
+			Name:           node.Node,
+			PrivateAddress: node.Address,
+			PublicAddress:  node.TaggedAddresses["wan"],
+			Region:         node.Datacenter,
+			Labels:         node.Meta,
+			Services:       services[node.Node],
+		}
+		if lan := node.TaggedAddresses["lan"]; lan != "" {
+			i.PrivateAddress = lan
+		}
+		sort.Strings(i.Services)
+		instances = append(instances, i)
+	}
+
+	return instances, nil
+}
+
+// services looks up the nodes providing each service of the catalog
+// concurrently and returns the services provided by each node
+func (c *ConsulClient) services(ctx context.Context, catalog map[string][]string) (map[string][]string, error) {
+	var mu sync.Mutex
+	services := map[string][]string{}
+
+	pool := pond.New(consulConcurrency, len(catalog))
+	defer pool.StopAndWait()
+
+	group, ctx := pool.GroupContext(ctx)
+	for name := range catalog {
+		name := name
+		group.Submit(func() error {
+			var entries []consulServiceNode
+			if err := c.get(ctx, "/v1/catalog/service/"+url.PathEscape(name), &entries); err != nil {
+				return err
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			for _, e := range entries {
+				services[e.Node] = append(services[e.Node], e.ServiceName)
+			}
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+	return services, nil
+}
+
+func (c *ConsulClient) get(ctx context.Context, path string, v interface{}) error {
+	u, err := url.Parse(c.address + path)
+	if err != nil {
+		return err
+	}
+	if c.datacenter != "" {
+		u.RawQuery = url.Values{"dc": {c.datacenter}}.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	if c.token != "" {
+		req.Header.Set("X-Consul-Token", c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("couldn't query Consul catalog: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("couldn't query Consul catalog: %s returned %s", path, resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,138 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// fakeConsul serves a catalog with a node per datacenter and checks that
+// every request carries the token
+func fakeConsul(t *testing.T, token string) *httptest.Server {
+	t.Helper()
+
+	catalog := map[string]map[string]interface{}{
+		"dc1": {
+			"/v1/catalog/nodes": []map[string]interface{}{
+				{
+					"Node":            "web-1",
+					"Address":         "10.0.0.1",
+					"Datacenter":      "dc1",
+					"TaggedAddresses": map[string]string{"lan": "10.0.1.1", "wan": "203.0.113.1"},
+					"Meta":            map[string]string{"role": "web", "env": "prod"},
+				},
+				{"Node": "db-1", "Address": "10.0.0.2", "Datacenter": "dc1"},
+			},
+			"/v1/catalog/services":       map[string][]string{"nginx": {}, "consul": {}},
+			"/v1/catalog/service/nginx":  []map[string]string{{"Node": "web-1", "ServiceName": "nginx"}},
+			"/v1/catalog/service/consul": []map[string]string{{"Node": "web-1", "ServiceName": "consul"}, {"Node": "db-1", "ServiceName": "consul"}},
+		},
+		"dc2": {
+			"/v1/catalog/nodes":    []map[string]interface{}{{"Node": "web-2", "Address": "10.1.0.1", "Datacenter": "dc2"}},
+			"/v1/catalog/services": map[string][]string{},
+		},
+	}
+
+	return newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Consul-Token") != token {
+			http.Error(w, "ACL not found", http.StatusForbidden)
+			return
+		}
+
+		// like Consul, the datacenter of the agent is used when none is given
+		dc := r.URL.Query().Get("dc")
+		if dc == "" {
+			dc = "dc1"
+		}
+		v, ok := catalog[dc][r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(v)
+	})
+}
+
+func TestConsulClientGetInstances(t *testing.T) {
+	srv := fakeConsul(t, "secret")
+
+	tests := []struct {
+		name     string
+		settings map[string]interface{}
+		want     []Instance
+	}{
+		{
+			name:     "agent datacenter",
+			settings: map[string]interface{}{"token": "secret"},
+			want: []Instance{
+				{
+					Name:           "web-1",
+					PrivateAddress: "10.0.1.1",
+					PublicAddress:  "203.0.113.1",
+					Region:         "dc1",
+					Labels:         map[string]string{"role": "web", "env": "prod"},
+					Services:       []string{"consul", "nginx"},
+				},
+				{Name: "db-1", PrivateAddress: "10.0.0.2", Region: "dc1", Services: []string{"consul"}},
+			},
+		},
+		{
+			name:     "other datacenter",
+			settings: map[string]interface{}{"token": "secret", "datacenter": "dc2"},
+			want:     []Instance{{Name: "web-2", PrivateAddress: "10.1.0.1", Region: "dc2"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestProvider(t, "consul", map[string]interface{}{"address": srv.URL}, tt.settings)
+			instances, err := p.GetInstances(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(instances, tt.want) {
+				t.Errorf("unexpected instances\ngot:  %+v\nwant: %+v", instances, tt.want)
+			}
+		})
+	}
+}
+
+func TestConsulClientGetInstancesToken(t *testing.T) {
+	srv := fakeConsul(t, "secret")
+
+	t.Run("missing", func(t *testing.T) {
+		t.Setenv("CONSUL_HTTP_TOKEN", "")
+		p := newTestProvider(t, "consul", map[string]interface{}{"address": srv.URL})
+		if _, err := p.GetInstances(context.Background()); err == nil {
+			t.Fatal("expected the catalog to refuse a request without the token")
+		}
+	})
+
+	t.Run("from environment", func(t *testing.T) {
+		t.Setenv("CONSUL_HTTP_TOKEN", "secret")
+		p := newTestProvider(t, "consul", map[string]interface{}{"address": srv.URL})
+		if _, err := p.GetInstances(context.Background()); err != nil {
+			t.Fatalf("expected the token to be read from the environment: %v", err)
+		}
+	})
+}
+
+func TestConsulClientGetInstancesTimeout(t *testing.T) {
+	stuck := make(chan struct{})
+	srv := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-stuck:
+		case <-r.Context().Done():
+		}
+	})
+	t.Cleanup(func() { close(stuck) })
+
+	p := newTestProvider(t, "consul", map[string]interface{}{"address": srv.URL, "timeout": "100ms"})
+	if _, err := p.GetInstances(context.Background()); err == nil {
+		t.Fatal("expected a stuck agent to time out")
+	}
+}
@@ -0,0 +1,167 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/apex/log"
+	"github.com/spf13/viper"
+)
+
+// source is a configured provider along with the name it's referred to by.
+type source struct {
+	name     string
+	key      string
+	provider Provider
+}
+
+// configuredSources returns the providers listed under discovery.providers or,
+// when there are none, the single provider selected by discovery.provider.
+func configuredSources() ([]source, error) {
+	var entries []map[string]interface{}
+	if err := viper.UnmarshalKey("discovery.providers", &entries); err != nil {
+		return nil, fmt.Errorf("couldn't parse discovery.providers: %v", err)
+	}
+
+	if len(entries) == 0 {
+		name := viper.GetString("discovery.provider")
+		if name == "" {
+			name = "gcp"
+		}
+
+		cfg := subConfig(name)
+		key := cacheKey(name, cfg.AllSettings())
+		p, err := NewProvider(name, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return []source{{name: name, key: key, provider: p}}, nil
+	}
+
+	sources := make([]source, 0, len(entries))
+	seen := map[string]bool{}
+	for n, entry := range entries {
+		kind, _ := entry["type"].(string)
+		if kind == "" {
+			return nil, fmt.Errorf("provider #%d in discovery.providers has no type", n+1)
+		}
+
+		name, _ := entry["name"].(string)
+		if name == "" {
+			name = kind
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("provider name \"%s\" is used more than once in discovery.providers, give each one a unique name", name)
+		}
+		seen[name] = true
+
+		cfg := viper.New()
+		if err := cfg.MergeConfigMap(entry); err != nil {
+			return nil, err
+		}
+
+		key := cacheKey(name, entry)
+		p, err := NewProvider(kind, cfg)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, source{name: name, key: key, provider: p})
+	}
+	return sources, nil
+}
+
+// discover queries all sources concurrently and merges their instances,
+// each instance is tagged with the name of the source it comes from.
+// Results younger than discovery.cache-ttl are served from the cache unless
+// refresh is set, local providers always read their instances afresh.
+func discover(ctx context.Context, sources []source, refresh bool) ([]Instance, error) {
+	ttl := viper.GetDuration("discovery.cache-ttl")
+	results := make([][]Instance, len(sources))
+	errs := make([]error, len(sources))
+
+	var wg sync.WaitGroup
+	for n, s := range sources {
+		wg.Add(1)
+		go func(n int, s source) {
+			defer wg.Done()
+			log := log.WithField("provider", s.name)
+
+			cacheable := s.key != ""
+			if p, ok := s.provider.(LocalProvider); ok && p.Local() {
+				cacheable = false
+			}
+			if cacheable && ttl > 0 && !refresh {
+				if instances, ok := readCache(s, ttl); ok {
+					log.Debug("Using cached instance list")
+					results[n] = instances
+					return
+				}
+			}
+
+			log.Info("Fetching instance list")
+			instances, err := s.provider.GetInstances(ctx)
+			if err != nil {
+				errs[n] = fmt.Errorf("provider \"%s\": %v", s.name, err)
+				return
+			}
+
+			for i := range instances {
+				instances[i].Provider = s.name
+			}
+			results[n] = instances
+
+			if cacheable && (ttl > 0 || refresh) {
+				if err := writeCache(s, instances); err != nil {
+					log.Warnf("Couldn't cache instance list: %v", err)
+				}
+			}
+		}(n, s)
+	}
+	wg.Wait()
+
+	var instances []Instance
+	for n := range sources {
+		if errs[n] != nil {
+			return nil, errs[n]
+		}
+		instances = append(instances, results[n]...)
+	}
+	return instances, nil
+}
+
+// Discover queries the given providers concurrently and merges their instances,
+// the cache is never used.
+func Discover(ctx context.Context, providers ...Provider) ([]Instance, error) {
+	sources := make([]source, 0, len(providers))
+	for _, p := range providers {
+		sources = append(sources, source{name: p.Name(), provider: p})
+	}
+
+	return discover(ctx, sources, false)
+}
+
+// Refresh fetches the instance list from every configured provider, bypassing
+// and updating the cache.
+func Refresh() ([]Instance, error) {
+	sources, err := configuredSources()
+	if err != nil {
+		return nil, err
+	}
+
+	return discover(context.Background(), sources, true)
+}
+
+// subConfig returns the settings found under the given key prefix,
+// including the ones coming from flags and environment variables.
+func subConfig(prefix string) *viper.Viper {
+	cfg := viper.New()
+	for _, key := range viper.AllKeys() {
+		if !strings.HasPrefix(key, prefix+".") || !viper.IsSet(key) {
+			continue
+		}
+		cfg.Set(strings.TrimPrefix(key, prefix+"."), viper.Get(key))
+	}
+	return cfg
+}
@@ -0,0 +1,46 @@
+package cloud
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestDiscoverSkipsCacheForLocalProviders(t *testing.T) {
+	dir := t.TempDir()
+	viper.Set("discovery.cache-dir", filepath.Join(dir, "cache"))
+	viper.Set("discovery.cache-ttl", time.Hour)
+	t.Cleanup(viper.Reset)
+
+	path := filepath.Join(dir, "inventory.toml")
+	write := func(name string) {
+		t.Helper()
+		host := "[[hosts]]\n  name = \"" + name + "\"\n  private-address = \"10.0.0.1\"\n"
+		if err := os.WriteFile(path, []byte(host), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	settings := map[string]interface{}{"path": path}
+	p := newTestProvider(t, "inventory", settings)
+	sources := []source{{name: "inventory", key: cacheKey("inventory", settings), provider: p}}
+
+	for _, name := range []string{"before", "after"} {
+		write(name)
+		instances, err := discover(context.Background(), sources, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(instances) != 1 || instances[0].Name != name {
+			t.Errorf("expected the edited inventory to be read, got %+v", instances)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "cache")); !os.IsNotExist(err) {
+		t.Errorf("expected no cache to be written for a local provider")
+	}
+}
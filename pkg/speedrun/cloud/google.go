@@ -3,51 +3,180 @@ package cloud
 import (
 	"context"
 	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/apex/log"
+	"github.com/spf13/viper"
 	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/option"
 )
 
+func init() {
+	Register("gcp", func() Provider { return &GoogleClient{} })
+}
+
 type GoogleClient struct {
 	*compute.Service
+	projects []string
+	nic      string
+	timeout  time.Duration
+}
+
+func (c *GoogleClient) Name() string {
+	return "gcp"
 }
 
-func NewGCPClient() (*GoogleClient, error) {
-	var err error
-	ctx := context.Background()
+func (c *GoogleClient) Schema() []Option {
+	return []Option{
+		{Key: "projectid", Description: "Google Cloud project ID or a list of them", Required: true},
+		{Key: "interface", Description: "name of the network interface to take addresses from, instances without it are skipped", Default: "nic0"},
+		{Key: "timeout", Description: "maximum time allowed to list the instances of a project", Default: "60s"},
+		{Key: "endpoint", Description: "override for the Compute Engine API endpoint, disables authentication"},
+	}
+}
 
-	gce, err := compute.NewService(ctx)
+func (c *GoogleClient) Configure(cfg *viper.Viper) error {
+	var opts []option.ClientOption
+	if endpoint := cfg.GetString("endpoint"); endpoint != "" {
+		opts = append(opts, option.WithEndpoint(endpoint), option.WithoutAuthentication())
+	}
+
+	gce, err := compute.NewService(context.Background(), opts...)
 	if err != nil {
-		err = fmt.Errorf("couldn't initialize GCP client: %v", err)
-		return nil, err
+		return fmt.Errorf("couldn't initialize GCP client: %v", err)
+	}
+
+	c.Service = gce
+	c.nic = cfg.GetString("interface")
+	c.timeout = cfg.GetDuration("timeout")
+	c.projects = cfg.GetStringSlice("projectid")
+	if len(c.projects) == 0 {
+		return fmt.Errorf("no Google Cloud project configured")
 	}
+	return nil
+}
+
+// GetInstances returns the list of instances found in the configured projects,
+// projects are queried concurrently.
+func (c *GoogleClient) GetInstances(ctx context.Context) ([]Instance, error) {
+	results := make([][]Instance, len(c.projects))
+	errs := make([]error, len(c.projects))
+
+	var wg sync.WaitGroup
+	for n, project := range c.projects {
+		wg.Add(1)
+		go func(n int, project string) {
+			defer wg.Done()
+			results[n], errs[n] = c.getProjectInstances(ctx, project)
+		}(n, project)
+	}
+	wg.Wait()
 
-	return &GoogleClient{gce}, nil
+	instances := []Instance{}
+	for n, project := range c.projects {
+		if errs[n] != nil {
+			return nil, fmt.Errorf("project \"%s\": %v", project, errs[n])
+		}
+		instances = append(instances, results[n]...)
+	}
+	return instances, nil
 }
 
-// GetInstances returns a list of external IP addresses used for the SHH connection
-func (c *GoogleClient) GetInstances(project string) ([]Instance, error) {
+func (c *GoogleClient) getProjectInstances(ctx context.Context, project string) ([]Instance, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
 	instances := []Instance{}
-	listCall := c.Instances.AggregatedList(project).Fields("nextPageToken", "items(Name,NetworkInterfaces,Labels)")
-	var ctx context.Context
+	listCall := c.Instances.AggregatedList(project).Fields("nextPageToken", "items(Name,NetworkInterfaces,Labels,Zone,Status,MachineType,Tags,Metadata,CreationTimestamp)")
 
-	listCall.Pages(ctx, func(list *compute.InstanceAggregatedList) error {
+	err := listCall.Pages(ctx, func(list *compute.InstanceAggregatedList) error {
 		for _, item := range list.Items {
 			for _, instance := range item.Instances {
-				i := Instance{
-					Name:           instance.Name,
-					PrivateAddress: instance.NetworkInterfaces[0].NetworkIP,
-					PublicAddress:  instance.NetworkInterfaces[0].AccessConfigs[0].NatIP,
-					Labels:         instance.Labels,
+				i, ok := c.instance(instance, project)
+				if !ok {
+					log.WithField("host", instance.Name).Warnf("Skipping instance without network interface \"%s\"", c.nic)
+					continue
 				}
 				instances = append(instances, i)
 			}
 		}
 		return nil
 	})
-	_, err := listCall.Do()
 	if err != nil {
 		return nil, err
 	}
 
 	return instances, nil
 }
+
+// instance converts a Compute Engine instance, addresses are taken from the
+// configured network interface, the public one is left empty when it has no
+// external IP. It reports false when the instance has no such interface.
+func (c *GoogleClient) instance(instance *compute.Instance, project string) (Instance, bool) {
+	i := Instance{
+		Name:        instance.Name,
+		Labels:      instance.Labels,
+		Project:     project,
+		Zone:        resourceName(instance.Zone),
+		Status:      instance.Status,
+		MachineType: resourceName(instance.MachineType),
+	}
+	i.Region = zoneRegion(i.Zone)
+
+	found := false
+	for _, nic := range instance.NetworkInterfaces {
+		if nic.Name != c.nic {
+			continue
+		}
+
+		found = true
+		i.PrivateAddress = nic.NetworkIP
+		for _, ac := range nic.AccessConfigs {
+			if ac.NatIP != "" {
+				i.PublicAddress = ac.NatIP
+				break
+			}
+		}
+		break
+	}
+
+	if instance.Tags != nil {
+		i.Tags = instance.Tags.Items
+	}
+	if instance.Metadata != nil {
+		i.Metadata = make(map[string]string, len(instance.Metadata.Items))
+		for _, item := range instance.Metadata.Items {
+			if item.Value != nil {
+				i.Metadata[item.Key] = *item.Value
+			}
+		}
+	}
+	if created, err := time.Parse(time.RFC3339, instance.CreationTimestamp); err == nil {
+		i.CreatedAt = created
+	}
+
+	return i, found
+}
+
+// resourceName returns the last segment of a resource URL, e.g. e2-small for
+// https://www.googleapis.com/compute/v1/projects/foo/zones/europe-west1-b/machineTypes/e2-small
+func resourceName(url string) string {
+	if url == "" {
+		return ""
+	}
+	return path.Base(url)
+}
+
+// zoneRegion returns the region a zone belongs to, e.g. europe-west1 for europe-west1-b
+func zoneRegion(zone string) string {
+	if n := strings.LastIndex(zone, "-"); n > 0 {
+		return zone[:n]
+	}
+	return zone
+}
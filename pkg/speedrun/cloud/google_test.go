@@ -0,0 +1,141 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeCompute serves the pages of an aggregated instance list, keyed by page
+// token, the first page has an empty token. Pages that aren't found fail.
+func fakeCompute(t *testing.T, pages map[string]string) *httptest.Server {
+	t.Helper()
+
+	return newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/aggregated/instances") {
+			http.NotFound(w, r)
+			return
+		}
+
+		page, ok := pages[r.URL.Query().Get("pageToken")]
+		if !ok {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]interface{}{"error": map[string]interface{}{"code": 500, "message": "backend error"}})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(page))
+	})
+}
+
+// fakeComputeSettings points the provider at the fake compute server
+func fakeComputeSettings(srv *httptest.Server) map[string]interface{} {
+	return map[string]interface{}{"projectid": "test-project", "endpoint": srv.URL + "/"}
+}
+
+func TestGoogleClientGetInstances(t *testing.T) {
+	srv := fakeCompute(t, map[string]string{
+		"": `{
+			"items": {
+				"zones/europe-west1-b": {
+					"instances": [
+						{
+							"name": "private-only",
+							"zone": "https://www.googleapis.com/compute/v1/projects/test-project/zones/europe-west1-b",
+							"machineType": "https://www.googleapis.com/compute/v1/projects/test-project/zones/europe-west1-b/machineTypes/e2-small",
+							"status": "RUNNING",
+							"labels": {"role": "db"},
+							"networkInterfaces": [{"name": "nic0", "networkIP": "10.0.0.1"}]
+						}
+					]
+				}
+			},
+			"nextPageToken": "page2"
+		}`,
+		"page2": `{
+			"items": {
+				"zones/us-east1-c": {
+					"instances": [
+						{
+							"name": "multi-nic",
+							"zone": "https://www.googleapis.com/compute/v1/projects/test-project/zones/us-east1-c",
+							"networkInterfaces": [
+								{"name": "nic0", "networkIP": "10.0.0.2", "accessConfigs": [{"natIP": "203.0.113.2"}]},
+								{"name": "nic1", "networkIP": "10.1.0.2", "accessConfigs": [{"name": "no-nat"}, {"natIP": "203.0.113.3"}]}
+							]
+						}
+					]
+				}
+			}
+		}`,
+	})
+
+	tests := []struct {
+		nic     string
+		names   []string
+		private []string
+		public  []string
+	}{
+		{nic: "nic0", names: []string{"private-only", "multi-nic"}, private: []string{"10.0.0.1", "10.0.0.2"}, public: []string{"", "203.0.113.2"}},
+		// the private-only instance has no nic1, it can't be reached
+		{nic: "nic1", names: []string{"multi-nic"}, private: []string{"10.1.0.2"}, public: []string{"203.0.113.3"}},
+		{nic: "nic2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nic, func(t *testing.T) {
+			p := newTestProvider(t, "gcp", fakeComputeSettings(srv), map[string]interface{}{"interface": tt.nic})
+			instances, err := p.GetInstances(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(instances) != len(tt.names) {
+				t.Fatalf("expected %d instances across both pages, got %d", len(tt.names), len(instances))
+			}
+			for n, i := range instances {
+				if i.Name != tt.names[n] {
+					t.Errorf("expected instance %s, got %s", tt.names[n], i.Name)
+				}
+				if i.PrivateAddress != tt.private[n] || i.PublicAddress != tt.public[n] {
+					t.Errorf("%s: expected addresses %q/%q, got %q/%q", i.Name, tt.private[n], tt.public[n], i.PrivateAddress, i.PublicAddress)
+				}
+			}
+		})
+	}
+
+	p := newTestProvider(t, "gcp", fakeComputeSettings(srv))
+	instances, err := p.GetInstances(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	i := instances[0]
+	if i.Name != "private-only" || i.Project != "test-project" || i.Zone != "europe-west1-b" || i.Region != "europe-west1" || i.MachineType != "e2-small" || i.Status != "RUNNING" || i.Labels["role"] != "db" {
+		t.Errorf("unexpected instance: %+v", i)
+	}
+	if i.GetAddress(false) != "10.0.0.1" {
+		t.Errorf("expected a private-only instance to fall back to its private address, got %q", i.GetAddress(false))
+	}
+	if instances[1].MachineType != "" {
+		t.Errorf("expected an empty machine type, got %q", instances[1].MachineType)
+	}
+}
+
+func TestGoogleClientGetInstancesPageError(t *testing.T) {
+	srv := fakeCompute(t, map[string]string{
+		"": `{"items": {"zones/europe-west1-b": {"instances": [{"name": "first", "networkInterfaces": [{"name": "nic0", "networkIP": "10.0.0.1"}]}]}}, "nextPageToken": "missing"}`,
+	})
+
+	p := newTestProvider(t, "gcp", fakeComputeSettings(srv))
+	instances, err := p.GetInstances(context.Background())
+	if err == nil {
+		t.Fatalf("expected the error of the second page, got %d instances", len(instances))
+	}
+	if !strings.Contains(err.Error(), "test-project") {
+		t.Errorf("expected the error to name the project, got: %v", err)
+	}
+}
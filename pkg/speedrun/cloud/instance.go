@@ -1,8 +1,10 @@
 package cloud
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
+	"time"
 
 	"github.com/antonmedv/expr"
 	"github.com/apex/log"
@@ -11,15 +13,35 @@ import (
 	"github.com/spf13/viper"
 )
 
+// ErrNoInstances is returned when no instance matches the target.
+var ErrNoInstances = errors.New("no instances matched the target")
+
+// DefaultPort is the port portals listen on unless told otherwise.
+const DefaultPort = 1337
+
 type Instance struct {
-	PublicAddress  string
-	PrivateAddress string
-	Name           string            `expr:"name"`
-	Labels         map[string]string `expr:"labels"`
+	PublicAddress  string            `json:"public_address,omitempty"`
+	PrivateAddress string            `json:"private_address,omitempty"`
+	Port           int               `json:"port,omitempty"`
+	Name           string            `json:"name" expr:"name"`
+	Labels         map[string]string `json:"labels,omitempty" expr:"labels"`
+	Services       []string          `json:"services,omitempty" expr:"services"`
+	Provider       string            `json:"provider,omitempty" expr:"provider"`
+	Project        string            `json:"project,omitempty" expr:"project"`
+	Zone           string            `json:"zone,omitempty" expr:"zone"`
+	Region         string            `json:"region,omitempty" expr:"region"`
+	Status         string            `json:"status,omitempty" expr:"status"`
+	MachineType    string            `json:"machine_type,omitempty" expr:"machine_type"`
+	Tags           []string          `json:"tags,omitempty" expr:"tags"`
+	Metadata       map[string]string `json:"metadata,omitempty" expr:"metadata"`
+	CreatedAt      time.Time         `json:"created_at" expr:"created_at"`
 }
 
+// GetAddress returns the private or the public address of the instance,
+// falling back to the other one when the instance lacks it. It's empty when
+// the instance has no address at all.
 func (i Instance) GetAddress(private bool) string {
-	if private {
+	if private && i.PrivateAddress != "" || i.PublicAddress == "" {
 		return i.PrivateAddress
 	}
 
@@ -27,30 +49,34 @@ func (i Instance) GetAddress(private bool) string {
 }
 
 func GetInstances(target string) ([]Instance, error) {
-	project := viper.GetString("gcp.projectid")
-	if project == "" {
-		return nil, fmt.Errorf("missing Google Cloud project ID, consider adding it to your config file: %s", viper.ConfigFileUsed())
+	sources, err := configuredSources()
+	if err != nil {
+		return nil, err
 	}
 
-	gcpClient, err := NewGCPClient()
+	instances, err := discover(context.Background(), sources, viper.GetBool("discovery.refresh"))
 	if err != nil {
 		return nil, err
 	}
 
-	log.Info("Fetching instance list")
-	instances, err := gcpClient.GetInstances(project)
+	subset, evalErrs, err := Filter(instances, target)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrap(err, "invalid target expression")
 	}
 
-	subset, err := filter(instances, target)
-	if len(subset) == 0 {
-		return nil, fmt.Errorf("no instances found")
+	for _, e := range evalErrs {
+		log.WithField("host", e.Instance.Name).Warn(e.Error())
 	}
 
-	if err != nil {
-		err = errors.Wrap(err, "could not filter instance list")
-		return nil, err
+	if len(evalErrs) > 0 && viper.GetBool("discovery.strict") {
+		return nil, fmt.Errorf("target expression couldn't be evaluated for %d out of %d instances, aborting due to strict mode", len(evalErrs), len(instances))
+	}
+
+	if len(subset) == 0 {
+		if len(evalErrs) > 0 {
+			return nil, errors.Wrapf(ErrNoInstances, "target expression couldn't be evaluated for %d out of %d instances", len(evalErrs), len(instances))
+		}
+		return nil, ErrNoInstances
 	}
 
 	return subset, nil
@@ -71,26 +97,46 @@ func SetupTLS() (*tls.Config, error) {
 
 }
 
-func filter(instnces []Instance, target string) ([]Instance, error) {
+// EvalError is returned when the target expression fails for a given instance.
+type EvalError struct {
+	Instance Instance
+	Err      error
+}
+
+func (e *EvalError) Error() string {
+	return fmt.Sprintf("couldn't evaluate target expression: %v", e.Err)
+}
+
+func (e *EvalError) Unwrap() error {
+	return e.Err
+}
+
+// Filter returns the instances matching the target along with the instances
+// for which the target couldn't be evaluated. An error is returned only if
+// the target itself is invalid.
+func Filter(instances []Instance, target string) ([]Instance, []*EvalError, error) {
 	if target == "" {
-		return instnces, nil
+		return instances, nil, nil
 	}
 
 	var subset []Instance
+	var evalErrs []*EvalError
 
 	program, err := expr.Compile(target, expr.Env(Instance{}), expr.AsBool())
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	for _, instance := range instnces {
+	for _, instance := range instances {
 		output, err := expr.Run(program, instance)
 		if err != nil {
+			evalErrs = append(evalErrs, &EvalError{Instance: instance, Err: err})
 			continue
 		}
 
 		match, ok := output.(bool)
 		if !ok {
+			evalErrs = append(evalErrs, &EvalError{Instance: instance, Err: fmt.Errorf("expected a boolean result, got %T", output)})
 			continue
 		}
 
@@ -98,5 +144,5 @@ func filter(instnces []Instance, target string) ([]Instance, error) {
 			subset = append(subset, instance)
 		}
 	}
-	return subset, nil
+	return subset, evalErrs, nil
 }
@@ -0,0 +1,26 @@
+package cloud
+
+import "testing"
+
+func TestInstanceGetAddress(t *testing.T) {
+	tests := []struct {
+		name     string
+		instance Instance
+		private  bool
+		want     string
+	}{
+		{name: "public", instance: Instance{PublicAddress: "203.0.113.1", PrivateAddress: "10.0.0.1"}, want: "203.0.113.1"},
+		{name: "private", instance: Instance{PublicAddress: "203.0.113.1", PrivateAddress: "10.0.0.1"}, private: true, want: "10.0.0.1"},
+		{name: "private only", instance: Instance{PrivateAddress: "10.0.0.1"}, want: "10.0.0.1"},
+		{name: "public only", instance: Instance{PublicAddress: "203.0.113.1"}, private: true, want: "203.0.113.1"},
+		{name: "none", instance: Instance{}, private: true, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.instance.GetAddress(tt.private); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
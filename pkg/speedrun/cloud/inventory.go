@@ -0,0 +1,86 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+)
+
+func init() {
+	Register("inventory", func() Provider { return &Inventory{} })
+}
+
+// Inventory is a provider backed by a static list of hosts read from a
+// TOML, YAML or JSON file.
+type Inventory struct {
+	path string
+}
+
+type inventoryHost struct {
+	Name           string            `mapstructure:"name"`
+	PublicAddress  string            `mapstructure:"public-address"`
+	PrivateAddress string            `mapstructure:"private-address"`
+	Port           int               `mapstructure:"port"`
+	Labels         map[string]string `mapstructure:"labels"`
+}
+
+func (i *Inventory) Name() string {
+	return "inventory"
+}
+
+func (i *Inventory) Schema() []Option {
+	return []Option{
+		{Key: "path", Description: "path to the inventory file", Required: true},
+	}
+}
+
+// Local reports that the inventory is read from a local file.
+func (i *Inventory) Local() bool {
+	return true
+}
+
+// Configure reads the path of the inventory file, a relative path is
+// resolved against the directory of the config file it comes from.
+func (i *Inventory) Configure(cfg *viper.Viper) error {
+	i.path = cfg.GetString("path")
+	if config := viper.ConfigFileUsed(); config != "" && !filepath.IsAbs(i.path) {
+		i.path = filepath.Join(filepath.Dir(config), i.path)
+	}
+	return nil
+}
+
+// GetInstances returns the hosts listed in the inventory file
+func (i *Inventory) GetInstances(_ context.Context) ([]Instance, error) {
+	v := viper.New()
+	v.SetConfigFile(i.path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("couldn't read inventory at \"%s\": %v", i.path, err)
+	}
+
+	var hosts []inventoryHost
+	if err := v.UnmarshalKey("hosts", &hosts); err != nil {
+		return nil, fmt.Errorf("couldn't parse inventory at \"%s\": %v", i.path, err)
+	}
+
+	instances := make([]Instance, 0, len(hosts))
+	for n, h := range hosts {
+		if h.Name == "" {
+			return nil, fmt.Errorf("host #%d in inventory \"%s\" has no name", n+1, i.path)
+		}
+		if h.PublicAddress == "" && h.PrivateAddress == "" {
+			return nil, fmt.Errorf("host \"%s\" in inventory \"%s\" has no address", h.Name, i.path)
+		}
+
+		instances = append(instances, Instance{
+			Name:           h.Name,
+			PublicAddress:  h.PublicAddress,
+			PrivateAddress: h.PrivateAddress,
+			Port:           h.Port,
+			Labels:         h.Labels,
+		})
+	}
+
+	return instances, nil
+}
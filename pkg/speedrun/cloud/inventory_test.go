@@ -0,0 +1,31 @@
+package cloud
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestInventoryPathRelativeToConfig(t *testing.T) {
+	dir := t.TempDir()
+	host := "[[hosts]]\n  name = \"web-1\"\n  private-address = \"10.0.0.1\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "inventory.toml"), []byte(host), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	viper.SetConfigFile(filepath.Join(dir, "speedrun.toml"))
+	t.Cleanup(viper.Reset)
+
+	p := newTestProvider(t, "inventory", map[string]interface{}{"path": "inventory.toml"})
+
+	instances, err := p.GetInstances(context.Background())
+	if err != nil {
+		t.Fatalf("expected the inventory to be found next to the config file: %v", err)
+	}
+	if len(instances) != 1 || instances[0].Name != "web-1" {
+		t.Errorf("unexpected instances: %+v", instances)
+	}
+}
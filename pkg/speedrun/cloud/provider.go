@@ -0,0 +1,98 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+// Provider discovers the instances on which portals are running.
+type Provider interface {
+	// Name returns the name under which the provider is registered.
+	Name() string
+	// Schema describes the configuration keys understood by the provider.
+	Schema() []Option
+	// Configure prepares the provider using its own configuration block.
+	Configure(cfg *viper.Viper) error
+	// GetInstances returns every instance known to the provider.
+	GetInstances(ctx context.Context) ([]Instance, error)
+}
+
+// LocalProvider is implemented by providers that read their instances from
+// the local machine, e.g. a file. Their instance lists are never cached, the
+// cache wouldn't be any cheaper and would hide the latest edits.
+type LocalProvider interface {
+	Provider
+	Local() bool
+}
+
+// Option describes a single configuration key of a provider.
+type Option struct {
+	Key         string
+	Description string
+	Default     interface{}
+	Required    bool
+}
+
+var (
+	providersMu sync.RWMutex
+	providers   = map[string]func() Provider{}
+)
+
+// Register makes a provider available under the given name.
+func Register(name string, factory func() Provider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+
+	if _, dup := providers[name]; dup {
+		panic(fmt.Sprintf("cloud: provider %q registered twice", name))
+	}
+	providers[name] = factory
+}
+
+// Providers returns the sorted names of all registered providers.
+func Providers() []string {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewProvider returns the provider registered under name, configured with cfg.
+// Defaults from the provider's schema are applied and required keys are
+// validated before the provider gets configured.
+func NewProvider(name string, cfg *viper.Viper) (Provider, error) {
+	providersMu.RLock()
+	factory, ok := providers[name]
+	providersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown provider \"%s\", available providers: %v", name, Providers())
+	}
+
+	if cfg == nil {
+		cfg = viper.New()
+	}
+
+	p := factory()
+	for _, o := range p.Schema() {
+		if o.Default != nil {
+			cfg.SetDefault(o.Key, o.Default)
+		}
+		if o.Required && !cfg.IsSet(o.Key) {
+			return nil, fmt.Errorf("missing \"%s\" for provider \"%s\" (%s), consider adding it to your config file: %s", o.Key, name, o.Description, viper.ConfigFileUsed())
+		}
+	}
+
+	if err := p.Configure(cfg); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
@@ -0,0 +1,38 @@
+package cloud
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// newTestServer serves handler until the end of the test, it stands in for
+// the API of a provider
+func newTestServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// newTestProvider returns the named provider configured with the given
+// settings, later ones take precedence over earlier ones
+func newTestProvider(t *testing.T, name string, settings ...map[string]interface{}) Provider {
+	t.Helper()
+
+	cfg := viper.New()
+	for _, s := range settings {
+		for k, v := range s {
+			cfg.Set(k, v)
+		}
+	}
+
+	p, err := NewProvider(name, cfg)
+	if err != nil {
+		t.Fatalf("couldn't create provider %s: %v", name, err)
+	}
+	return p
+}
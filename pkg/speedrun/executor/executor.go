@@ -0,0 +1,358 @@
+// Package executor runs operations against the portals of many instances
+// concurrently and collects a result for each of them.
+package executor
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alitto/pond"
+	"github.com/dpogorzelski/speedrun/pkg/speedrun/cloud"
+	portalpb "github.com/dpogorzelski/speedrun/proto/portal"
+	"storj.io/drpc/drpcconn"
+)
+
+const (
+	DefaultConcurrency = 1000
+	DefaultDialTimeout = 5 * time.Second
+	DefaultTimeout     = 10 * time.Second
+	DefaultPortLabel   = "speedrun-port"
+)
+
+// Config holds the settings shared by every operation sent to the portals.
+type Config struct {
+	// TLS is used to connect to the portals.
+	TLS *tls.Config
+	// Concurrency is the maximum number of portals contacted at the same time.
+	Concurrency int
+	// DialTimeout bounds the time spent connecting to a single portal.
+	DialTimeout time.Duration
+	// Timeout bounds the time spent on the operation once connected to a portal.
+	Timeout time.Duration
+	// Port is used for instances that don't specify their own.
+	Port int
+	// PortLabel is the label that overrides Port for a given instance.
+	PortLabel string
+	// UsePrivateIP makes the executor connect to the private address of the instances.
+	UsePrivateIP bool
+	// BatchSize splits the instances into batches that are run one after the
+	// other, 0 runs every instance at once.
+	BatchSize int
+	// BatchPause is the time to wait between two batches.
+	BatchPause time.Duration
+	// HealthCheck, if set, is performed on every instance right after the
+	// operation succeeded. Any failure, of the operation or of the health
+	// check, aborts the rollout.
+	HealthCheck Operation
+	// Canary is the number of instances run first, on their own. Any failure
+	// among them aborts the rollout. It's ignored if it covers every instance.
+	Canary int
+	// MaxFailures aborts the rollout once this many instances failed, 0
+	// disables the limit. Instances already in flight are still waited for,
+	// so the limit can be overshot by up to Concurrency-1 failures.
+	MaxFailures int
+	// MaxFailureRate aborts the rollout once the percentage of failed
+	// instances reaches it, 0 disables the limit.
+	MaxFailureRate float64
+	// StrictThresholds keeps no more instances in flight than the failures
+	// still allowed by MaxFailures and MaxFailureRate so that they are never
+	// overshot, e.g. instances are run one at a time with MaxFailures 1.
+	StrictThresholds bool
+	// OnBatch, if set, is called before each batch starts.
+	OnBatch func(batch, batches int, instances []cloud.Instance)
+}
+
+// Operation is performed against the portal of a single instance. It may
+// return a response along with an error, e.g. the output of a command that
+// failed.
+type Operation func(ctx context.Context, client portalpb.DRPCPortalClient, instance cloud.Instance) (*Response, error)
+
+// Response is what an operation got back from a portal.
+type Response struct {
+	State portalpb.State
+	// Message describes the outcome of the operation.
+	Message string
+	// Stdout and Stderr hold the output of the operation, e.g. the output of a
+	// command or the contents of a file.
+	Stdout string
+	Stderr string
+	// ExitCode is set by operations that run a command.
+	ExitCode *int
+	// Reply is the message returned by the portal, if the operation kept it.
+	Reply interface{}
+}
+
+// Result is the outcome of an operation on a single instance.
+type Result struct {
+	Response
+	Instance cloud.Instance
+	// Address is the host:port the executor connects to for the instance.
+	Address  string
+	Duration time.Duration
+	Err      error
+}
+
+// Unreachable reports whether the portal of the instance couldn't be reached
+// at all, as opposed to an operation that failed once connected.
+func (r Result) Unreachable() bool {
+	var derr *DialError
+	return errors.As(r.Err, &derr)
+}
+
+// ErrNoAddress is the error of the instances lacking the address the executor
+// is configured to use, they are never dialed.
+var ErrNoAddress = errors.New("instance has no address")
+
+// DialError is returned when the connection to a portal couldn't be established.
+type DialError struct {
+	Err error
+}
+
+func (e *DialError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *DialError) Unwrap() error {
+	return e.Err
+}
+
+type Executor struct {
+	cfg Config
+}
+
+// New returns an executor, zero values in cfg are replaced with defaults.
+func New(cfg Config) *Executor {
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = DefaultConcurrency
+	}
+	if cfg.DialTimeout <= 0 {
+		cfg.DialTimeout = DefaultDialTimeout
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if cfg.Port == 0 {
+		cfg.Port = cloud.DefaultPort
+	}
+	if cfg.PortLabel == "" {
+		cfg.PortLabel = DefaultPortLabel
+	}
+
+	return &Executor{cfg: cfg}
+}
+
+// Run performs op on every instance and returns the results in the same order
+// as instances. If onResult isn't nil it's called as soon as each result is
+// available, calls are never concurrent. Once the rollout is aborted no new
+// instance is contacted, the remaining ones are reported with ErrSkipped and
+// don't go through onResult.
+func (e *Executor) Run(ctx context.Context, instances []cloud.Instance, op Operation, onResult func(Result)) []Result {
+	results := make([]Result, len(instances))
+	var mu sync.Mutex
+	failures := 0
+	aborted := false
+
+	// with strict thresholds, no more instances than the failures still
+	// allowed are in flight so that the thresholds can't be overshot
+	threshold := 0
+	if e.cfg.StrictThresholds {
+		threshold = e.threshold(len(instances))
+	}
+	inFlight := 0
+	cond := sync.NewCond(&mu)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			mu.Lock()
+			cond.Broadcast()
+			mu.Unlock()
+		case <-done:
+		}
+	}()
+
+	batches := e.batches(len(instances))
+	for b, batch := range batches {
+		if b > 0 && e.cfg.BatchPause > 0 && !aborted {
+			select {
+			case <-time.After(e.cfg.BatchPause):
+			case <-ctx.Done():
+			}
+		}
+
+		if !aborted && ctx.Err() == nil && e.cfg.OnBatch != nil {
+			e.cfg.OnBatch(b+1, len(batches), instances[batch[0]:batch[1]])
+		}
+
+		pool := pond.New(e.cfg.Concurrency, batch[1]-batch[0])
+		for n := batch[0]; n < batch[1]; n++ {
+			n, instance := n, instances[n]
+			pool.Submit(func() {
+				mu.Lock()
+				for threshold > 0 && !aborted && ctx.Err() == nil && inFlight >= threshold-failures {
+					cond.Wait()
+				}
+				stop := aborted || ctx.Err() != nil
+				if !stop {
+					inFlight++
+				}
+				mu.Unlock()
+				if stop {
+					results[n] = Result{Instance: instance, Address: e.Address(instance), Err: ErrSkipped}
+					return
+				}
+
+				r := e.execute(ctx, instance, op)
+
+				mu.Lock()
+				defer mu.Unlock()
+				defer cond.Broadcast()
+				inFlight--
+				results[n] = r
+				if r.Err != nil {
+					failures++
+					// with a health check every instance has to pass the
+					// gate, failing before reaching it counts the same
+					if e.cfg.HealthCheck != nil || e.exceeded(failures, len(instances)) {
+						aborted = true
+					}
+				}
+				if onResult != nil {
+					onResult(r)
+				}
+			})
+		}
+		pool.StopAndWait()
+
+		if b == 0 && e.canary(len(instances)) > 0 && failures > 0 {
+			aborted = true
+		}
+	}
+
+	return results
+}
+
+// batches returns the [start, end) ranges of the batches: the canary batch,
+// if any, followed by batches of BatchSize instances.
+func (e *Executor) batches(total int) [][2]int {
+	canary := e.canary(total)
+	if canary == 0 {
+		return split(total, e.cfg.BatchSize)
+	}
+
+	batches := [][2]int{{0, canary}}
+	for _, b := range split(total-canary, e.cfg.BatchSize) {
+		batches = append(batches, [2]int{b[0] + canary, b[1] + canary})
+	}
+	return batches
+}
+
+// canary returns the size of the canary batch, 0 if there's none or if it
+// would cover every instance
+func (e *Executor) canary(total int) int {
+	if e.cfg.Canary <= 0 || e.cfg.Canary >= total {
+		return 0
+	}
+	return e.cfg.Canary
+}
+
+// threshold returns the number of failures crossing the thresholds, 0 if
+// there are none
+func (e *Executor) threshold(total int) int {
+	if e.cfg.MaxFailures <= 0 && e.cfg.MaxFailureRate <= 0 {
+		return 0
+	}
+
+	failures := 1
+	for !e.exceeded(failures, total) && failures < total {
+		failures++
+	}
+	return failures
+}
+
+// exceeded reports whether the failure thresholds have been crossed, a
+// threshold is crossed as soon as it's reached
+func (e *Executor) exceeded(failures, total int) bool {
+	if e.cfg.MaxFailures > 0 && failures >= e.cfg.MaxFailures {
+		return true
+	}
+
+	return e.cfg.MaxFailureRate > 0 && float64(failures)*100/float64(total) >= e.cfg.MaxFailureRate
+}
+
+// Address returns the address the executor connects to for the given instance.
+// The port set on the instance, e.g. by an inventory file, comes first, then
+// the one found in the port label and finally the configured one. It's empty
+// when the instance has no address to connect to.
+func (e *Executor) Address(instance cloud.Instance) string {
+	host := instance.GetAddress(e.cfg.UsePrivateIP)
+	if host == "" {
+		return ""
+	}
+
+	port := instance.Port
+	if port == 0 {
+		if p, err := strconv.Atoi(instance.Labels[e.cfg.PortLabel]); err == nil && p > 0 {
+			port = p
+		}
+	}
+	if port == 0 {
+		port = e.cfg.Port
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+func (e *Executor) execute(ctx context.Context, instance cloud.Instance, op Operation) (r Result) {
+	start := time.Now()
+	r = Result{Instance: instance, Address: e.Address(instance)}
+	defer func() { r.Duration = time.Since(start) }()
+
+	// an empty host would be dialed as the local machine
+	if r.Address == "" {
+		r.Err = &DialError{Err: ErrNoAddress}
+		return r
+	}
+
+	dialCtx, cancel := context.WithTimeout(ctx, e.cfg.DialTimeout)
+	defer cancel()
+
+	dialer := &tls.Dialer{Config: e.cfg.TLS}
+	rawconn, err := dialer.DialContext(dialCtx, "tcp", r.Address)
+	if err != nil {
+		r.Err = &DialError{Err: err}
+		return r
+	}
+
+	conn := drpcconn.New(rawconn)
+	defer conn.Close()
+
+	opCtx, cancel := context.WithTimeout(ctx, e.cfg.Timeout)
+	defer cancel()
+
+	client := portalpb.NewDRPCPortalClient(conn)
+	resp, err := op(opCtx, client, instance)
+	if resp != nil {
+		r.Response = *resp
+	}
+	if err != nil {
+		r.Err = err
+		return r
+	}
+
+	if e.cfg.HealthCheck != nil {
+		checkCtx, cancel := context.WithTimeout(ctx, e.cfg.Timeout)
+		defer cancel()
+
+		if _, err := e.cfg.HealthCheck(checkCtx, client, instance); err != nil {
+			r.Err = &HealthCheckError{Err: err}
+		}
+	}
+	return r
+}
@@ -0,0 +1,180 @@
+package executor
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/dpogorzelski/speedrun/pkg/common/cryptoutil"
+	"github.com/dpogorzelski/speedrun/pkg/speedrun/cloud"
+	portalpb "github.com/dpogorzelski/speedrun/proto/portal"
+)
+
+// listen accepts TLS connections without serving anything on them, enough
+// for operations that don't send requests, and returns n instances pointing
+// at it
+func listen(t *testing.T, n int) (Config, []cloud.Instance) {
+	t.Helper()
+
+	tlsConfig, err := cryptoutil.InsecureTLSConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", tlsConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { lis.Close() })
+
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				conn.(*tls.Conn).Handshake()
+				var b [1]byte
+				conn.Read(b[:])
+				conn.Close()
+			}()
+		}
+	}()
+
+	port := lis.Addr().(*net.TCPAddr).Port
+	instances := make([]cloud.Instance, n)
+	for i := range instances {
+		instances[i] = cloud.Instance{Name: string(rune('a' + i)), PrivateAddress: "127.0.0.1", Port: port}
+	}
+	return Config{TLS: tlsConfig, UsePrivateIP: true}, instances
+}
+
+// failing returns an operation that always fails and counts its calls
+func failing(calls *int32) Operation {
+	return func(context.Context, portalpb.DRPCPortalClient, cloud.Instance) (*Response, error) {
+		atomic.AddInt32(calls, 1)
+		return nil, errors.New("failed")
+	}
+}
+
+func TestRunHealthCheckAbortsOnFailedOperation(t *testing.T) {
+	cfg, instances := listen(t, 3)
+	cfg.BatchSize = 1
+	cfg.HealthCheck = func(context.Context, portalpb.DRPCPortalClient, cloud.Instance) (*Response, error) {
+		return &Response{}, nil
+	}
+
+	var calls int32
+	results := New(cfg).Run(context.Background(), instances, failing(&calls), nil)
+
+	if calls != 1 {
+		t.Errorf("expected the rollout to stop after the first batch, the operation ran %d times", calls)
+	}
+	for _, r := range results[1:] {
+		if !errors.Is(r.Err, ErrSkipped) {
+			t.Errorf("%s: expected the host to be skipped, got: %v", r.Instance.Name, r.Err)
+		}
+	}
+}
+
+func TestRunStrictThresholds(t *testing.T) {
+	tests := []struct {
+		name  string
+		cfg   Config
+		calls int32
+	}{
+		{name: "max failures", cfg: Config{MaxFailures: 2}, calls: 2},
+		// 20% of 10 instances
+		{name: "max failure rate", cfg: Config{MaxFailureRate: 20}, calls: 2},
+		{name: "max failure rate rounded up", cfg: Config{MaxFailureRate: 25}, calls: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, instances := listen(t, 10)
+			cfg.MaxFailures = tt.cfg.MaxFailures
+			cfg.MaxFailureRate = tt.cfg.MaxFailureRate
+			cfg.StrictThresholds = true
+
+			var calls int32
+			results := New(cfg).Run(context.Background(), instances, failing(&calls), nil)
+
+			if calls != tt.calls {
+				t.Errorf("expected %d instances to be contacted, got %d", tt.calls, calls)
+			}
+
+			var skipped int32
+			for _, r := range results {
+				if errors.Is(r.Err, ErrSkipped) {
+					skipped++
+				}
+			}
+			if skipped != int32(len(instances))-tt.calls {
+				t.Errorf("expected the %d other instances to be skipped, got %d", int32(len(instances))-tt.calls, skipped)
+			}
+		})
+	}
+}
+
+func TestRunCanaryCoveringEveryInstance(t *testing.T) {
+	cfg, instances := listen(t, 3)
+	cfg.Canary = 3
+	cfg.BatchSize = 1
+
+	var calls int32
+	New(cfg).Run(context.Background(), instances, failing(&calls), nil)
+
+	if calls != 3 {
+		t.Errorf("expected a canary covering every instance to be ignored, %d instances were contacted", calls)
+	}
+}
+
+func TestRunResultAddress(t *testing.T) {
+	cfg, instances := listen(t, 2)
+	cfg.MaxFailures = 1
+	cfg.StrictThresholds = true
+
+	var calls int32
+	results := New(cfg).Run(context.Background(), instances, failing(&calls), nil)
+
+	// the skipped host carries its address as well
+	want := net.JoinHostPort("127.0.0.1", strconv.Itoa(instances[0].Port))
+	skipped := 0
+	for _, r := range results {
+		if r.Address != want {
+			t.Errorf("%s: expected address %s, got %s (%v)", r.Instance.Name, want, r.Address, r.Err)
+		}
+		if errors.Is(r.Err, ErrSkipped) {
+			skipped++
+		}
+	}
+	if skipped != 1 {
+		t.Errorf("expected one host to be skipped, got %d", skipped)
+	}
+}
+
+func TestRunInstanceWithoutAddress(t *testing.T) {
+	cfg, instances := listen(t, 1)
+	// the instance still points at the port of the local listener, which
+	// must not be mistaken for it
+	instances[0].PrivateAddress = ""
+
+	var calls int32
+	op := func(context.Context, portalpb.DRPCPortalClient, cloud.Instance) (*Response, error) {
+		atomic.AddInt32(&calls, 1)
+		return &Response{}, nil
+	}
+	results := New(cfg).Run(context.Background(), instances, op, nil)
+
+	if calls != 0 {
+		t.Errorf("expected the instance not to be dialed")
+	}
+	if !errors.Is(results[0].Err, ErrNoAddress) || !results[0].Unreachable() {
+		t.Errorf("expected the instance to be unreachable for lack of address, got: %v", results[0].Err)
+	}
+}
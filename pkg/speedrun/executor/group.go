@@ -0,0 +1,66 @@
+package executor
+
+import (
+	"errors"
+	"net"
+	"sort"
+)
+
+// Group is a set of hosts that produced the same output or failed with the
+// same error.
+type Group struct {
+	Hosts    []string
+	Stdout   string
+	Stderr   string
+	ExitCode *int
+	Err      error
+}
+
+type groupKey struct {
+	stdout   string
+	stderr   string
+	exitCode int
+	hasCode  bool
+	err      string
+}
+
+// GroupResults groups the hosts with identical output and error, the biggest
+// groups come first.
+func GroupResults(results []Result) []Group {
+	var groups []Group
+	index := map[groupKey]int{}
+	for _, r := range results {
+		err := groupError(r.Err)
+		k := groupKey{stdout: r.Stdout, stderr: r.Stderr}
+		if r.ExitCode != nil {
+			k.exitCode, k.hasCode = *r.ExitCode, true
+		}
+		if err != nil {
+			k.err = err.Error()
+		}
+
+		n, ok := index[k]
+		if !ok {
+			n = len(groups)
+			index[k] = n
+			groups = append(groups, Group{Stdout: r.Stdout, Stderr: r.Stderr, ExitCode: r.ExitCode, Err: err})
+		}
+		groups[n].Hosts = append(groups[n].Hosts, r.Instance.Name)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return len(groups[i].Hosts) > len(groups[j].Hosts)
+	})
+	return groups
+}
+
+// groupError strips the address from connection errors so that every host
+// that refused the connection ends up in the same group
+func groupError(err error) error {
+	var derr *DialError
+	var operr *net.OpError
+	if errors.As(err, &derr) && errors.As(derr.Err, &operr) {
+		return &DialError{Err: operr.Err}
+	}
+	return err
+}
@@ -0,0 +1,68 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrSkipped is the error of the instances that weren't contacted because
+// the rollout was aborted.
+var ErrSkipped = errors.New("skipped, rollout was aborted")
+
+// HealthCheckError is returned when the operation succeeded on an instance
+// but the health check that followed didn't.
+type HealthCheckError struct {
+	Err error
+}
+
+func (e *HealthCheckError) Error() string {
+	return fmt.Sprintf("health check failed: %v", e.Err)
+}
+
+func (e *HealthCheckError) Unwrap() error {
+	return e.Err
+}
+
+// ParseSize turns a batch or canary size given either as a number of
+// instances, e.g. "10", or as a percentage of total, e.g. "25%", into a number
+// of instances. Percentages are rounded up so that batches are never empty.
+func ParseSize(size string, total int) (int, error) {
+	if size == "" {
+		return 0, nil
+	}
+
+	if p, ok := strings.CutSuffix(size, "%"); ok {
+		percent, err := strconv.ParseFloat(p, 64)
+		if err != nil || percent <= 0 || percent > 100 {
+			return 0, fmt.Errorf("invalid size \"%s\", percentage must be between 0 and 100", size)
+		}
+		return int(math.Ceil(float64(total) * percent / 100)), nil
+	}
+
+	n, err := strconv.Atoi(size)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size \"%s\", expected a number of instances or a percentage", size)
+	}
+	return n, nil
+}
+
+// split divides n items into consecutive [start, end) ranges of at most size
+// items, a size of 0 means a single range.
+func split(n, size int) [][2]int {
+	if size <= 0 || size >= n {
+		return [][2]int{{0, n}}
+	}
+
+	var ranges [][2]int
+	for start := 0; start < n; start += size {
+		end := start + size
+		if end > n {
+			end = n
+		}
+		ranges = append(ranges, [2]int{start, end})
+	}
+	return ranges
+}
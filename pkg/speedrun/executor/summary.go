@@ -0,0 +1,51 @@
+package executor
+
+import (
+	"errors"
+
+	portalpb "github.com/dpogorzelski/speedrun/proto/portal"
+)
+
+// Summary groups the hosts of a run by outcome.
+type Summary struct {
+	Total int
+	// Succeeded holds every host the operation succeeded on, whether it
+	// changed anything or not.
+	Succeeded   []string
+	Changed     []string
+	Unchanged   []string
+	Failed      []string
+	Unreachable []string
+	Skipped     []string
+}
+
+// Summarize returns the summary of the given results.
+func Summarize(results []Result) Summary {
+	s := Summary{Total: len(results)}
+	for _, r := range results {
+		name := r.Instance.Name
+		switch {
+		case errors.Is(r.Err, ErrSkipped):
+			s.Skipped = append(s.Skipped, name)
+		case r.Unreachable():
+			s.Unreachable = append(s.Unreachable, name)
+		case r.Err != nil:
+			s.Failed = append(s.Failed, name)
+		default:
+			s.Succeeded = append(s.Succeeded, name)
+			switch r.State {
+			case portalpb.State_CHANGED:
+				s.Changed = append(s.Changed, name)
+			case portalpb.State_UNCHANGED:
+				s.Unchanged = append(s.Unchanged, name)
+			}
+		}
+	}
+	return s
+}
+
+// Failures returns the number of hosts the operation didn't succeed on,
+// skipped ones included.
+func (s Summary) Failures() int {
+	return s.Total - len(s.Succeeded)
+}
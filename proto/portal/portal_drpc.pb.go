@@ -43,6 +43,7 @@ type DRPCPortalClient interface {
 	ServiceStop(ctx context.Context, in *ServiceRequest) (*ServiceResponse, error)
 	ServiceStatus(ctx context.Context, in *ServiceRequest) (*ServiceStatusResponse, error)
 	RunCommand(ctx context.Context, in *CommandRequest) (*CommandResponse, error)
+	RunCommandStream(ctx context.Context, in *CommandRequest) (DRPCPortal_RunCommandStreamClient, error)
 	CPUusage(ctx context.Context, in *CPUusageRequest) (*CPUusageResponse, error)
 	FileRead(ctx context.Context, in *FileReadRequest) (*FileReadResponse, error)
 	FileCp(ctx context.Context, in *FileCpRequest) (*FileCpResponse, error)
@@ -106,6 +107,46 @@ func (c *drpcPortalClient) RunCommand(ctx context.Context, in *CommandRequest) (
 	return out, nil
 }
 
+func (c *drpcPortalClient) RunCommandStream(ctx context.Context, in *CommandRequest) (DRPCPortal_RunCommandStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, "/portal.Portal/RunCommandStream", drpcEncoding_File_portal_portal_proto{})
+	if err != nil {
+		return nil, err
+	}
+	x := &drpcPortal_RunCommandStreamClient{stream}
+	if err := x.MsgSend(in, drpcEncoding_File_portal_portal_proto{}); err != nil {
+		return nil, err
+	}
+	if err := x.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DRPCPortal_RunCommandStreamClient interface {
+	drpc.Stream
+	Recv() (*CommandOutput, error)
+}
+
+type drpcPortal_RunCommandStreamClient struct {
+	drpc.Stream
+}
+
+func (x *drpcPortal_RunCommandStreamClient) GetStream() drpc.Stream {
+	return x.Stream
+}
+
+func (x *drpcPortal_RunCommandStreamClient) Recv() (*CommandOutput, error) {
+	m := new(CommandOutput)
+	if err := x.MsgRecv(m, drpcEncoding_File_portal_portal_proto{}); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (x *drpcPortal_RunCommandStreamClient) RecvMsg(m *CommandOutput) error {
+	return x.MsgRecv(m, drpcEncoding_File_portal_portal_proto{})
+}
+
 func (c *drpcPortalClient) CPUusage(ctx context.Context, in *CPUusageRequest) (*CPUusageResponse, error) {
 	out := new(CPUusageResponse)
 	err := c.cc.Invoke(ctx, "/portal.Portal/CPUusage", drpcEncoding_File_portal_portal_proto{}, in, out)
@@ -166,6 +207,7 @@ type DRPCPortalServer interface {
 	ServiceStop(context.Context, *ServiceRequest) (*ServiceResponse, error)
 	ServiceStatus(context.Context, *ServiceRequest) (*ServiceStatusResponse, error)
 	RunCommand(context.Context, *CommandRequest) (*CommandResponse, error)
+	RunCommandStream(*CommandRequest, DRPCPortal_RunCommandStreamStream) error
 	CPUusage(context.Context, *CPUusageRequest) (*CPUusageResponse, error)
 	FileRead(context.Context, *FileReadRequest) (*FileReadResponse, error)
 	FileCp(context.Context, *FileCpRequest) (*FileCpResponse, error)
@@ -196,6 +238,10 @@ func (s *DRPCPortalUnimplementedServer) RunCommand(context.Context, *CommandRequ
 	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
 }
 
+func (s *DRPCPortalUnimplementedServer) RunCommandStream(*CommandRequest, DRPCPortal_RunCommandStreamStream) error {
+	return drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
 func (s *DRPCPortalUnimplementedServer) CPUusage(context.Context, *CPUusageRequest) (*CPUusageResponse, error) {
 	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
 }
@@ -222,7 +268,7 @@ func (s *DRPCPortalUnimplementedServer) SystemShutdown(context.Context, *SystemS
 
 type DRPCPortalDescription struct{}
 
-func (DRPCPortalDescription) NumMethods() int { return 11 }
+func (DRPCPortalDescription) NumMethods() int { return 12 }
 
 func (DRPCPortalDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver, interface{}, bool) {
 	switch n {
@@ -272,6 +318,15 @@ func (DRPCPortalDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver
 					)
 			}, DRPCPortalServer.RunCommand, true
 	case 5:
+		return "/portal.Portal/RunCommandStream", drpcEncoding_File_portal_portal_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return nil, srv.(DRPCPortalServer).
+					RunCommandStream(
+						in1.(*CommandRequest),
+						&drpcPortal_RunCommandStreamStream{in2.(drpc.Stream)},
+					)
+			}, DRPCPortalServer.RunCommandStream, true
+	case 6:
 		return "/portal.Portal/CPUusage", drpcEncoding_File_portal_portal_proto{},
 			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
 				return srv.(DRPCPortalServer).
@@ -280,7 +335,7 @@ func (DRPCPortalDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver
 						in1.(*CPUusageRequest),
 					)
 			}, DRPCPortalServer.CPUusage, true
-	case 6:
+	case 7:
 		return "/portal.Portal/FileRead", drpcEncoding_File_portal_portal_proto{},
 			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
 				return srv.(DRPCPortalServer).
@@ -289,7 +344,7 @@ func (DRPCPortalDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver
 						in1.(*FileReadRequest),
 					)
 			}, DRPCPortalServer.FileRead, true
-	case 7:
+	case 8:
 		return "/portal.Portal/FileCp", drpcEncoding_File_portal_portal_proto{},
 			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
 				return srv.(DRPCPortalServer).
@@ -298,7 +353,7 @@ func (DRPCPortalDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver
 						in1.(*FileCpRequest),
 					)
 			}, DRPCPortalServer.FileCp, true
-	case 8:
+	case 9:
 		return "/portal.Portal/FileChmod", drpcEncoding_File_portal_portal_proto{},
 			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
 				return srv.(DRPCPortalServer).
@@ -307,7 +362,7 @@ func (DRPCPortalDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver
 						in1.(*FileChmodRequest),
 					)
 			}, DRPCPortalServer.FileChmod, true
-	case 9:
+	case 10:
 		return "/portal.Portal/SystemReboot", drpcEncoding_File_portal_portal_proto{},
 			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
 				return srv.(DRPCPortalServer).
@@ -316,7 +371,7 @@ func (DRPCPortalDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver
 						in1.(*SystemRebootRequest),
 					)
 			}, DRPCPortalServer.SystemReboot, true
-	case 10:
+	case 11:
 		return "/portal.Portal/SystemShutdown", drpcEncoding_File_portal_portal_proto{},
 			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
 				return srv.(DRPCPortalServer).
@@ -414,6 +469,19 @@ func (x *drpcPortal_RunCommandStream) SendAndClose(m *CommandResponse) error {
 	return x.CloseSend()
 }
 
+type DRPCPortal_RunCommandStreamStream interface {
+	drpc.Stream
+	Send(*CommandOutput) error
+}
+
+type drpcPortal_RunCommandStreamStream struct {
+	drpc.Stream
+}
+
+func (x *drpcPortal_RunCommandStreamStream) Send(m *CommandOutput) error {
+	return x.MsgSend(m, drpcEncoding_File_portal_portal_proto{})
+}
+
 type DRPCPortal_CPUusageStream interface {
 	drpc.Stream
 	SendAndClose(*CPUusageResponse) error